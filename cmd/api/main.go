@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"nexus/internal/adapter/http/shared/middleware"
+	"nexus/internal/adapter/http/shared/response"
 	"nexus/internal/adapter/http/v1/router"
 	"nexus/internal/infrastructure/config"
 	"nexus/internal/infrastructure/database"
+	"nexus/pkg/lifecycle"
 	"nexus/pkg/logger"
 	"os"
 	"os/signal"
@@ -20,6 +23,11 @@ import (
 	jwtpkg "nexus/pkg/jwt"
 )
 
+const (
+	defaultShutdownTimeout = 10 * time.Second
+	adminRole              = "admin"
+)
+
 func main() {
 	// Config
 	cfg, err := config.Load()
@@ -32,30 +40,65 @@ func main() {
 		slog.String("environment", cfg.App.Environment),
 		slog.String("version", cfg.App.Version))
 
+	// Info level, the default logger filters out debug records
+	if cfg.App.Debug {
+		slog.Info("Effective configuration", slog.String("config", cfg.String()))
+	}
+
+	// Shutdown hooks, run in reverse order of registration
+	closer := lifecycle.New()
+	closer.Register("logger", func(ctx context.Context) error {
+		return logger.Default().Flush()
+	})
+
 	// Connect to db
 	db, err := database.NewPostgresConnection(&cfg.Database)
 	if err != nil {
 		logger.Fatal("Failed to connect to database", slog.Any("error", err))
 	}
-	defer func() {
-		if err := db.Close(); err != nil {
-			logger.Error("Failed to close database connection", slog.Any("error", err))
-		}
-	}()
+	closer.Register("database", func(ctx context.Context) error {
+		return db.Close()
+	})
+
+	pool := database.NewDB(db, &cfg.Database)
+
+	// Background tasks, stopped on shutdown
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	go pool.LogStats(bgCtx, time.Minute)
+
+	// Hot reload of runtime-tunable settings
+	go config.Watch(bgCtx, "", func(newCfg *config.AppConfig) {
+		pool.ApplyPoolConfig(&newCfg.Database)
+
+		logger.Info("Configuration reloaded",
+			slog.Int("max_open_conns", newCfg.Database.MaxOpenConns),
+			slog.Int("max_idle_conns", newCfg.Database.MaxIdleConns))
+	})
 
 	// Init JWT
+	jwtOpts := []jwtpkg.Option{
+		jwtpkg.WithIssuer(cfg.JWT.Issuer),
+		jwtpkg.WithAudience(cfg.JWT.Audience...),
+		jwtpkg.WithLeeway(cfg.JWT.Leeway),
+	}
+	if cfg.JWT.RefreshSecret != "" {
+		jwtOpts = append(jwtOpts, jwtpkg.WithRefreshSecret(cfg.JWT.RefreshSecret))
+	}
+
 	jwtManager := jwtpkg.NewJWTManager(
 		cfg.JWT.Secret,
 		cfg.JWT.AccessTokenDuration,
 		cfg.JWT.RefreshTokenDuration,
+		jwtOpts...,
 	)
 
 	// Init shared middleware
 	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
-	_ = authMiddleware
 
 	// Init modules
-	healthRouter := router.InitHealthModule()
+	healthRouter := router.InitHealthModule(pool)
 
 	// HTTP server
 
@@ -63,7 +106,22 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Route registration and internal errors in the same format as our logs
+	gin.DefaultWriter = logger.NewSlogWriter(slog.LevelDebug)
+	gin.DefaultErrorWriter = logger.NewSlogWriter(slog.LevelError)
+
 	r := gin.New()
+	r.Use(middleware.RequestID())
+	r.Use(middleware.Recovery(cfg.App.Environment != "production"))
+	r.Use(middleware.CORS(cfg.CORS))
+	if cfg.Compression.Enabled {
+		r.Use(middleware.Compress(cfg.Compression))
+	}
+
+	// JSON envelope instead of gin's plain-text defaults
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(response.NoRoute)
+	r.NoMethod(response.NoMethod)
 
 	// Routes
 	api := r.Group("/api")
@@ -74,28 +132,21 @@ func main() {
 		v1Router.Setup(v1)
 	}
 
-	srv := &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler:      r,
-		ReadTimeout:  cfg.Server.ReadTimeout,
-		WriteTimeout: cfg.Server.WriteTimeout,
-	}
+	mountDebugRoutes(r, cfg.Server, authMiddleware)
 
-	go func() {
-		host := cfg.Server.Host
-		if host == "0.0.0.0" || host == "" {
-			host = "localhost"
-		}
+	srv := newHTTPServer(cfg.Server, r)
+	closer.Register("http server", srv.Shutdown)
 
-		logger.Info("Server started",
-			slog.Int("port", cfg.Server.Port),
-			slog.String("host", cfg.Server.Host),
-			slog.String("health_check", fmt.Sprintf("http://%s:%d/api/v1/health", host, cfg.Server.Port)),
-			slog.String("environment", cfg.App.Environment),
-		)
+	listener, err := listen(srv, cfg)
+	if err != nil {
+		logger.Fatal("Failed to bind server address",
+			slog.String("addr", srv.Addr),
+			slog.Any("error", err))
+	}
 
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatal("Failed to start server", slog.Any("error", err))
+	go func() {
+		if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("Server stopped unexpectedly", slog.Any("error", err))
 		}
 	}()
 
@@ -105,12 +156,71 @@ func main() {
 
 	logger.Info("Exiting server gracefully...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownTimeout := cfg.Server.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
+	shutdownStart := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
-		logger.Fatal("Server forced to exit", slog.Any("error", err))
+	stopBackground()
+
+	if err := closer.Close(ctx); err != nil {
+		logger.Error("Shutdown finished with errors",
+			slog.Any("error", err),
+			slog.Duration("timeout", shutdownTimeout))
+		os.Exit(1)
+	}
+
+	logger.Info("Server exited gracefully",
+		slog.Duration("duration", time.Since(shutdownStart)))
+}
+
+// Mounts /debug/pprof for admins when server.pprof is enabled, never by default
+func mountDebugRoutes(r *gin.Engine, cfg config.ServerSection, auth *middleware.AuthMiddleware) {
+	if !cfg.Pprof {
+		return
 	}
 
-	logger.Info("Server exited gracefully")
+	router.NewDebugRouter().Setup(r.Group("/debug/pprof"),
+		auth.RequireAuth(),
+		auth.RequireRoles(adminRole))
+
+	logger.Warn("Profiling endpoints enabled", slog.String("path", "/debug/pprof"))
+}
+
+// Binds before reporting success, so a busy port fails startup right away
+// instead of logging that the server is listening
+func listen(srv *http.Server, cfg *config.AppConfig) (net.Listener, error) {
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	host := cfg.Server.Host
+	if host == "0.0.0.0" || host == "" {
+		host = "localhost"
+	}
+
+	logger.Info("Server listening",
+		slog.Int("port", cfg.Server.Port),
+		slog.String("host", cfg.Server.Host),
+		slog.String("health_check", fmt.Sprintf("http://%s:%d/api/v1/health", host, cfg.Server.Port)),
+		slog.String("environment", cfg.App.Environment),
+	)
+
+	return listener, nil
+}
+
+func newHTTPServer(cfg config.ServerSection, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
 }
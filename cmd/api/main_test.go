@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"nexus/internal/adapter/http/shared/middleware"
+	"nexus/internal/infrastructure/config"
+	jwtpkg "nexus/pkg/jwt"
+	"nexus/pkg/logger"
+	"nexus/pkg/uuidv7"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	logger.Init(logger.Config{Output: io.Discard})
+}
+
+func TestNewHTTPServer(t *testing.T) {
+	cfg := config.ServerSection{
+		Port:              8080,
+		ReadTimeout:       15 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      20 * time.Second,
+		IdleTimeout:       90 * time.Second,
+	}
+	handler := http.NewServeMux()
+
+	srv := newHTTPServer(cfg, handler)
+
+	if srv.Addr != ":8080" {
+		t.Errorf("Addr = %q, want :8080", srv.Addr)
+	}
+	if srv.Handler != handler {
+		t.Error("Handler not set")
+	}
+	if srv.ReadHeaderTimeout != cfg.ReadHeaderTimeout {
+		t.Errorf("ReadHeaderTimeout = %v, want %v", srv.ReadHeaderTimeout, cfg.ReadHeaderTimeout)
+	}
+	if srv.IdleTimeout != cfg.IdleTimeout {
+		t.Errorf("IdleTimeout = %v, want %v", srv.IdleTimeout, cfg.IdleTimeout)
+	}
+	if srv.ReadTimeout != cfg.ReadTimeout || srv.WriteTimeout != cfg.WriteTimeout {
+		t.Errorf("ReadTimeout, WriteTimeout = %v, %v, want %v, %v",
+			srv.ReadTimeout, srv.WriteTimeout, cfg.ReadTimeout, cfg.WriteTimeout)
+	}
+}
+
+func TestMountDebugRoutes(t *testing.T) {
+	jwtManager := jwtpkg.NewJWTManager("test-secret", 15*time.Minute, 24*time.Hour)
+	auth := middleware.NewAuthMiddleware(jwtManager)
+
+	tokenWithRoles := func(roles ...string) string {
+		pair, err := jwtManager.GenerateTokenPairWithClaims(uuidv7.New(), "user@example.com", roles, nil)
+		if err != nil {
+			t.Fatalf("GenerateTokenPairWithClaims: %v", err)
+		}
+		return pair.AccessToken
+	}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		token   string
+		want    int
+	}{
+		{"disabled", false, tokenWithRoles(adminRole), http.StatusNotFound},
+		{"enabled without token", true, "", http.StatusUnauthorized},
+		{"enabled without admin role", true, tokenWithRoles("user"), http.StatusForbidden},
+		{"enabled as admin", true, tokenWithRoles(adminRole), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			mountDebugRoutes(r, config.ServerSection{Pprof: tt.enabled}, auth)
+
+			req := httptest.NewRequest(http.MethodGet, "/debug/pprof/goroutine?debug=1", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger.Init(logger.Config{Format: "json", Output: &buf})
+	t.Cleanup(func() { logger.Init(logger.Config{Output: io.Discard}) })
+	return &buf
+}
+
+func TestListenPortInUse(t *testing.T) {
+	busy, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer busy.Close()
+
+	buf := captureLogs(t)
+	cfg := &config.AppConfig{}
+	cfg.Server.Port = busy.Addr().(*net.TCPAddr).Port
+
+	listener, err := listen(newHTTPServer(cfg.Server, http.NewServeMux()), cfg)
+	if err == nil {
+		listener.Close()
+		t.Fatal("listen succeeded on a port in use")
+	}
+	if !errors.Is(err, syscall.EADDRINUSE) {
+		t.Errorf("err = %v, want %v", err, syscall.EADDRINUSE)
+	}
+	if strings.Contains(buf.String(), "Server listening") {
+		t.Errorf("logged success after a failed bind: %s", buf.String())
+	}
+}
+
+func TestListenLogsAfterBind(t *testing.T) {
+	buf := captureLogs(t)
+	cfg := &config.AppConfig{}
+
+	listener, err := listen(&http.Server{Addr: "127.0.0.1:0"}, cfg)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	if !strings.Contains(buf.String(), "Server listening") {
+		t.Errorf("logs = %s, want the listening record", buf.String())
+	}
+}
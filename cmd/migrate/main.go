@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"nexus/internal/infrastructure/config"
+	"nexus/internal/infrastructure/database"
+	"nexus/pkg/migration"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const usage = `Usage: migrate [flags] <command>
+
+Commands:
+  up                         apply pending migrations (core + -modules)
+  down N                     roll back N migrations of -namespace
+  status                     show the state of every namespace
+  version                    print the current version of -namespace
+  create <namespace> <desc>  create empty up/down files for the next version
+  force <version>            record -namespace at version and clear dirty state
+
+Flags:
+`
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+var errUsage = errors.New("invalid usage")
+
+type command struct {
+	name        string
+	steps       int
+	version     int
+	namespace   string
+	description string
+}
+
+type options struct {
+	configPath    string
+	migrationsDir string
+	namespace     string
+	modules       []string
+	allowDataLoss bool
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	opts, cmd, err := parseArgs(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	if err := execute(ctx, opts, cmd, stdout); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitError
+	}
+	return exitOK
+}
+
+func parseArgs(args []string, stderr io.Writer) (options, command, error) {
+	var opts options
+	var modules string
+
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&opts.configPath, "config", "", "config file (default config/app.yaml)")
+	fs.StringVar(&opts.migrationsDir, "dir", "migrations", "migrations directory")
+	fs.StringVar(&opts.namespace, "namespace", "core", "namespace for down, version and force")
+	fs.StringVar(&modules, "modules", "", "comma-separated modules migrated by up after core")
+	fs.BoolVar(&opts.allowDataLoss, "allow-data-loss", false, "let down run migrations that drop tables or columns")
+
+	if err := fs.Parse(args); err != nil {
+		return opts, command{}, err
+	}
+
+	for _, module := range strings.Split(modules, ",") {
+		if module = strings.TrimSpace(module); module != "" {
+			opts.modules = append(opts.modules, module)
+		}
+	}
+
+	cmd, err := parseCommand(fs.Args())
+	if err != nil {
+		fs.Usage()
+		return opts, command{}, err
+	}
+
+	return opts, cmd, nil
+}
+
+func parseCommand(args []string) (command, error) {
+	if len(args) == 0 {
+		return command{}, fmt.Errorf("%w: missing command", errUsage)
+	}
+
+	cmd := command{name: args[0]}
+	rest := args[1:]
+
+	switch cmd.name {
+	case "up", "status", "version":
+		if len(rest) != 0 {
+			return command{}, fmt.Errorf("%w: %s takes no arguments", errUsage, cmd.name)
+		}
+	case "down":
+		if len(rest) != 1 {
+			return command{}, fmt.Errorf("%w: down takes the number of steps", errUsage)
+		}
+		steps, err := strconv.Atoi(rest[0])
+		if err != nil || steps <= 0 {
+			return command{}, fmt.Errorf("%w: steps must be a positive number, got %q", errUsage, rest[0])
+		}
+		cmd.steps = steps
+	case "force":
+		if len(rest) != 1 {
+			return command{}, fmt.Errorf("%w: force takes a version", errUsage)
+		}
+		version, err := strconv.Atoi(rest[0])
+		if err != nil || version < 0 {
+			return command{}, fmt.Errorf("%w: version must be a non-negative number, got %q", errUsage, rest[0])
+		}
+		cmd.version = version
+	case "create":
+		if len(rest) < 2 {
+			return command{}, fmt.Errorf("%w: create takes a namespace and a description", errUsage)
+		}
+		cmd.namespace = rest[0]
+		cmd.description = strings.Join(rest[1:], " ")
+	default:
+		return command{}, fmt.Errorf("%w: unknown command %q", errUsage, cmd.name)
+	}
+
+	return cmd, nil
+}
+
+func execute(ctx context.Context, opts options, cmd command, stdout io.Writer) error {
+	// Only touches the filesystem, works without a database
+	if cmd.name == "create" {
+		manager := migration.NewManager(nil, opts.migrationsDir)
+		upPath, downPath, err := manager.Create(cmd.namespace, cmd.description)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Created %s\nCreated %s\n", upPath, downPath)
+		return nil
+	}
+
+	// config.Load with the path from -config, which Load itself can't take
+	cfg, err := config.LoadAppConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+
+	db, err := database.NewPostgresConnection(&cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return runCommand(ctx, migration.NewManager(db, opts.migrationsDir), opts, cmd, stdout)
+}
+
+// Runs a command that needs the database against manager
+func runCommand(ctx context.Context, manager migration.Manager, opts options, cmd command, stdout io.Writer) error {
+	switch cmd.name {
+	case "up":
+		if err := manager.MigrateAll(ctx, opts.modules); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, "Migrations applied")
+	case "down":
+		var rollbackOpts []migration.RollbackOption
+		if opts.allowDataLoss {
+			rollbackOpts = append(rollbackOpts, migration.AllowDataLoss())
+		}
+		rolledBack, err := manager.Rollback(ctx, opts.namespace, cmd.steps, rollbackOpts...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Rolled back %d migration(s) of %s\n", rolledBack, opts.namespace)
+	case "version":
+		version, err := manager.Version(ctx, opts.namespace)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "%s: %d\n", opts.namespace, version)
+	case "force":
+		if err := manager.Force(ctx, opts.namespace, cmd.version); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Forced %s to version %d\n", opts.namespace, cmd.version)
+	case "status":
+		statuses, err := manager.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printStatus(stdout, statuses)
+	}
+
+	return nil
+}
+
+func printStatus(w io.Writer, statuses map[string]migration.MigrationStatus) {
+	namespaces := make([]string, 0, len(statuses))
+	for namespace := range statuses {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	fmt.Fprintf(w, "%-20s %8s %8s  %s\n", "NAMESPACE", "VERSION", "PENDING", "STATE")
+	for _, namespace := range namespaces {
+		status := statuses[namespace]
+		state := "clean"
+		if status.Dirty {
+			state = "dirty"
+		}
+		fmt.Fprintf(w, "%-20s %8d %8d  %s\n", namespace, status.CurrentVersion, status.PendingCount, state)
+	}
+
+	for _, namespace := range namespaces {
+		if missing := statuses[namespace].MissingFiles; len(missing) > 0 {
+			fmt.Fprintf(w, "Warning: %s has applied versions without files: %v\n", namespace, missing)
+		}
+	}
+}
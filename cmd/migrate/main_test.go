@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"nexus/pkg/migration"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    command
+		wantErr bool
+	}{
+		{args: []string{"up"}, want: command{name: "up"}},
+		{args: []string{"status"}, want: command{name: "status"}},
+		{args: []string{"version"}, want: command{name: "version"}},
+		{args: []string{"down", "2"}, want: command{name: "down", steps: 2}},
+		{args: []string{"force", "0"}, want: command{name: "force", version: 0}},
+		{args: []string{"force", "7"}, want: command{name: "force", version: 7}},
+		{args: []string{"create", "core", "add", "users"}, want: command{name: "create", namespace: "core", description: "add users"}},
+
+		{args: nil, wantErr: true},
+		{args: []string{"sideways"}, wantErr: true},
+		{args: []string{"up", "extra"}, wantErr: true},
+		{args: []string{"down"}, wantErr: true},
+		{args: []string{"down", "0"}, wantErr: true},
+		{args: []string{"down", "two"}, wantErr: true},
+		{args: []string{"force"}, wantErr: true},
+		{args: []string{"force", "-1"}, wantErr: true},
+		{args: []string{"create", "core"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := parseCommand(tt.args)
+			if tt.wantErr {
+				if !errors.Is(err, errUsage) {
+					t.Fatalf("err = %v, want %v", err, errUsage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCommand: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("command = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArgsFlags(t *testing.T) {
+	opts, cmd, err := parseArgs([]string{
+		"-dir", "db/migrations",
+		"-namespace", "billing",
+		"-modules", "billing, ,notifications",
+		"-allow-data-loss",
+		"down", "1",
+	}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseArgs: %v", err)
+	}
+
+	if opts.migrationsDir != "db/migrations" || opts.namespace != "billing" || !opts.allowDataLoss {
+		t.Errorf("options = %+v", opts)
+	}
+	if len(opts.modules) != 2 || opts.modules[0] != "billing" || opts.modules[1] != "notifications" {
+		t.Errorf("modules = %q, want [billing notifications]", opts.modules)
+	}
+	if cmd.name != "down" || cmd.steps != 1 {
+		t.Errorf("command = %+v", cmd)
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	missingConfig := filepath.Join(dir, "missing.yaml")
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"help", []string{"-h"}, exitOK},
+		{"no command", nil, exitUsage},
+		{"unknown command", []string{"sideways"}, exitUsage},
+		{"unknown flag", []string{"-verbose", "up"}, exitUsage},
+		{"bad steps", []string{"down", "x"}, exitUsage},
+		{"create", []string{"-dir", dir, "create", "core", "add users"}, exitOK},
+		{"config error", []string{"-config", missingConfig, "status"}, exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr strings.Builder
+			if got := run(context.Background(), tt.args, io.Discard, &stderr); got != tt.want {
+				t.Errorf("exit code = %d, want %d (stderr: %s)", got, tt.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunCreateWritesFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	var stdout strings.Builder
+	if code := run(context.Background(), []string{"-dir", dir, "create", "core", "Add users"}, &stdout, io.Discard); code != exitOK {
+		t.Fatalf("exit code = %d", code)
+	}
+
+	for _, name := range []string{"000001_add_users.up.sql", "000001_add_users.down.sql"} {
+		path := filepath.Join(dir, "core", name)
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s not created: %v", name, err)
+		}
+		if !strings.Contains(stdout.String(), path) {
+			t.Errorf("output doesn't mention %s: %s", path, stdout.String())
+		}
+	}
+}
+
+// Manager with a number of applied migrations, only Rollback is implemented
+type stubManager struct {
+	migration.Manager
+	applied int
+}
+
+func (m *stubManager) Rollback(_ context.Context, _ string, steps int, _ ...migration.RollbackOption) (int, error) {
+	n := min(steps, m.applied)
+	m.applied -= n
+	return n, nil
+}
+
+func TestRunCommandDownReportsRolledBack(t *testing.T) {
+	tests := []struct {
+		name    string
+		applied int
+		steps   int
+		want    string
+	}{
+		{"fewer than applied", 3, 2, "Rolled back 2 migration(s) of core\n"},
+		{"more than applied", 2, 5, "Rolled back 2 migration(s) of core\n"},
+		{"nothing applied", 0, 3, "Rolled back 0 migration(s) of core\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout strings.Builder
+			manager := &stubManager{applied: tt.applied}
+			cmd := command{name: "down", steps: tt.steps}
+			if err := runCommand(context.Background(), manager, options{namespace: "core"}, cmd, &stdout); err != nil {
+				t.Fatalf("runCommand: %v", err)
+			}
+			if stdout.String() != tt.want {
+				t.Errorf("output = %q, want %q", stdout.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"nexus/internal/adapter/http/shared/response"
+	"nexus/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	apiKeyHeader       = "X-API-Key"
+	apiKeyPrincipalKey = "api_key_principal"
+)
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// Caller identified by an API key
+type APIKeyPrincipal struct {
+	ID     string
+	Name   string
+	Scopes []string
+}
+
+type APIKeyValidator interface {
+	// Returns ErrInvalidAPIKey when the key is unknown or revoked
+	ValidateAPIKey(ctx context.Context, key string) (*APIKeyPrincipal, error)
+}
+
+// Authenticates server-to-server callers by static API key, independent of JWT
+type APIKeyMiddleware struct {
+	validator APIKeyValidator
+}
+
+func NewAPIKeyMiddleware(validator APIKeyValidator) *APIKeyMiddleware {
+	return &APIKeyMiddleware{
+		validator: validator,
+	}
+}
+
+// Requires valid X-API-Key header
+func (m *APIKeyMiddleware) RequireAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(apiKeyHeader)
+		if key == "" {
+			response.Error(c, http.StatusUnauthorized, "api key required", nil)
+			c.Abort()
+			return
+		}
+
+		principal, err := m.validator.ValidateAPIKey(c.Request.Context(), key)
+		if errors.Is(err, ErrInvalidAPIKey) || (err == nil && principal == nil) {
+			response.Error(c, http.StatusUnauthorized, "invalid api key", nil)
+			c.Abort()
+			return
+		}
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "failed to validate api key", err)
+			c.Abort()
+			return
+		}
+
+		// Save principal to context, scopes are shared with RequireAnyScope
+		c.Set(apiKeyPrincipalKey, principal)
+		c.Set(userScopesKey, principal.Scopes)
+
+		ctx := context.WithValue(c.Request.Context(), logger.UserIDKey, principal.ID)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+func GetAPIKeyPrincipal(c *gin.Context) (*APIKeyPrincipal, bool) {
+	value, exists := c.Get(apiKeyPrincipalKey)
+	if !exists {
+		return nil, false
+	}
+
+	principal, ok := value.(*APIKeyPrincipal)
+	return principal, ok
+}
@@ -1,11 +1,16 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"nexus/internal/adapter/http/shared/response"
 	jwtpkg "nexus/pkg/jwt"
+	"nexus/pkg/logger"
 	"nexus/pkg/uuidv7"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,43 +20,117 @@ const (
 	authorizationPrefix = "Bearer "
 	userIDKey           = "user_id"
 	userEmailKey        = "user_email"
+	userRolesKey        = "user_roles"
+	userScopesKey       = "user_scopes"
 )
 
+const defaultValidationTimeout = 2 * time.Second
+
 type AuthMiddleware struct {
-	jwtManager *jwtpkg.JWTManager
+	jwtManager        *jwtpkg.JWTManager
+	cookieName        string
+	validationTimeout time.Duration
+}
+
+type AuthOption func(*AuthMiddleware)
+
+// Reads the token from the given cookie when Authorization header is absent
+func WithTokenCookie(name string) AuthOption {
+	return func(m *AuthMiddleware) {
+		m.cookieName = name
+	}
 }
 
-func NewAuthMiddleware(jwtManager *jwtpkg.JWTManager) *AuthMiddleware {
-	return &AuthMiddleware{
-		jwtManager: jwtManager,
+// Bounds the revocation check, a slow token store fails the request with 503
+func WithValidationTimeout(timeout time.Duration) AuthOption {
+	return func(m *AuthMiddleware) {
+		m.validationTimeout = timeout
 	}
 }
 
+func NewAuthMiddleware(jwtManager *jwtpkg.JWTManager, opts ...AuthOption) *AuthMiddleware {
+	m := &AuthMiddleware{
+		jwtManager:        jwtManager,
+		validationTimeout: defaultValidationTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
 // Requires valid JWT token
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
+	return m.requireAuth("")
+}
+
+// Like RequireAuth, but as a last resort also reads the token from the given
+// query parameter (e.g. "access_token"), for EventSource/WebSocket clients that
+// can't set headers. Lower security: URLs end up in proxy and access logs, so
+// only use it on the routes that need it, with short-lived tokens
+func (m *AuthMiddleware) RequireAuthWithQuery(param string) gin.HandlerFunc {
+	return m.requireAuth(param)
+}
+
+func (m *AuthMiddleware) requireAuth(queryParam string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := m.extractToken(c)
+		if token == "" && queryParam != "" {
+			token = takeQueryToken(c, queryParam)
+		}
 		if token == "" {
-			response.Error(c, http.StatusUnauthorized, "authorization header required", nil)
+			response.ErrorWithCode(c, http.StatusUnauthorized, response.CodeUnauthenticated, "authorization header required", nil)
 			c.Abort()
 			return
 		}
 
-		claims, err := m.jwtManager.ValidateToken(token)
+		claims, err := m.validateToken(c, token)
 		if err != nil {
-			response.Error(c, http.StatusUnauthorized, "invalid or expired token", err)
+			if errors.Is(err, jwtpkg.ErrTokenStoreUnavailable) {
+				response.Error(c, http.StatusServiceUnavailable, "token validation unavailable", nil)
+			} else if errors.Is(err, jwtpkg.ErrExpiredToken) {
+				response.ErrorWithCode(c, http.StatusUnauthorized, response.CodeTokenExpired, "token has expired", err)
+			} else {
+				response.ErrorWithCode(c, http.StatusUnauthorized, response.CodeTokenInvalid, "invalid token", err)
+			}
 			c.Abort()
 			return
 		}
 
 		// Save user data to context
-		c.Set(userIDKey, claims.UserID)
-		c.Set(userEmailKey, claims.Email)
+		setClaims(c, claims)
 
 		c.Next()
 	}
 }
 
+// Same as RequireAuth, but lets routes whose matched path (e.g. "/api/v1/webhooks/:id")
+// is one of the prefixes or lies below it through without a token.
+// Prefixes match whole segments, "/webhooks" doesn't cover "/webhooks-admin"
+func (m *AuthMiddleware) RequireAuthExcept(prefixes ...string) gin.HandlerFunc {
+	requireAuth := m.RequireAuth()
+
+	return func(c *gin.Context) {
+		// Empty for unmatched routes, which still require auth
+		if path := c.FullPath(); path != "" {
+			for _, prefix := range prefixes {
+				if hasPathPrefix(path, prefix) {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		requireAuth(c)
+	}
+}
+
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
+
 // Tries to extract token but doesn't require it
 func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -61,27 +140,110 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := m.jwtManager.ValidateToken(token)
+		claims, err := m.validateToken(c, token)
 		if err == nil {
-			c.Set(userIDKey, claims.UserID)
-			c.Set(userEmailKey, claims.Email)
+			setClaims(c, claims)
+		}
+
+		c.Next()
+	}
+}
+
+// Requires the user to have at least one of the given roles. Must run after RequireAuth
+func (m *AuthMiddleware) RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRoles, ok := GetUserRoles(c)
+		if !ok {
+			response.Error(c, http.StatusUnauthorized, "authentication required", nil)
+			c.Abort()
+			return
+		}
+
+		if !containsAny(userRoles, roles) {
+			response.Error(c, http.StatusForbidden, "insufficient role", nil)
+			c.Abort()
+			return
 		}
 
 		c.Next()
 	}
 }
 
+// Requires the token to grant at least one of the given scopes. Must run after RequireAuth
+func (m *AuthMiddleware) RequireAnyScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userScopes, ok := GetUserScopes(c)
+		if !ok {
+			response.Error(c, http.StatusUnauthorized, "authentication required", nil)
+			c.Abort()
+			return
+		}
+
+		if !containsAny(userScopes, scopes) {
+			response.Error(c, http.StatusForbidden, "insufficient scope", nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (m *AuthMiddleware) validateToken(c *gin.Context, token string) (*jwtpkg.Claims, error) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), m.validationTimeout)
+	defer cancel()
+
+	return m.jwtManager.ValidateTokenContext(ctx, token, jwtpkg.TokenTypeAccess)
+}
+
+func setClaims(c *gin.Context, claims *jwtpkg.Claims) {
+	c.Set(userIDKey, claims.UserID)
+	c.Set(userEmailKey, claims.Email)
+	c.Set(userRolesKey, claims.Roles)
+	c.Set(userScopesKey, claims.Scopes)
+
+	// Tag logs emitted via logger.FromContext(c.Request.Context())
+	ctx := context.WithValue(c.Request.Context(), logger.UserIDKey, claims.UserID.String())
+	c.Request = c.Request.WithContext(ctx)
+}
+
+func containsAny(have, want []string) bool {
+	for _, w := range want {
+		if slices.Contains(have, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// Removes the token from the request URL, so handlers and request logs don't see it
+func takeQueryToken(c *gin.Context, param string) string {
+	query := c.Request.URL.Query()
+	token := query.Get(param)
+	if token != "" {
+		query.Del(param)
+		c.Request.URL.RawQuery = query.Encode()
+	}
+	return token
+}
+
+// Header takes precedence over cookie
 func (m *AuthMiddleware) extractToken(c *gin.Context) string {
 	authHeader := c.GetHeader(authorizationHeader)
-	if authHeader == "" {
-		return ""
+	if authHeader != "" {
+		if !strings.HasPrefix(authHeader, authorizationPrefix) {
+			return ""
+		}
+		return strings.TrimPrefix(authHeader, authorizationPrefix)
 	}
 
-	if !strings.HasPrefix(authHeader, authorizationPrefix) {
-		return ""
+	if m.cookieName != "" {
+		if token, err := c.Cookie(m.cookieName); err == nil {
+			return token
+		}
 	}
 
-	return strings.TrimPrefix(authHeader, authorizationPrefix)
+	return ""
 }
 
 // Helper functions to get data from the context
@@ -105,6 +267,26 @@ func GetUserEmail(c *gin.Context) (string, bool) {
 	return email, ok
 }
 
+func GetUserRoles(c *gin.Context) ([]string, bool) {
+	value, exists := c.Get(userRolesKey)
+	if !exists {
+		return nil, false
+	}
+
+	roles, ok := value.([]string)
+	return roles, ok
+}
+
+func GetUserScopes(c *gin.Context) ([]string, bool) {
+	value, exists := c.Get(userScopesKey)
+	if !exists {
+		return nil, false
+	}
+
+	scopes, ok := value.([]string)
+	return scopes, ok
+}
+
 // gets UserID or panics (for protected routes)
 func MustGetUserID(c *gin.Context) uuidv7.UUID {
 	userID, ok := GetUserID(c)
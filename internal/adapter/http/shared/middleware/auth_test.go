@@ -0,0 +1,407 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"nexus/internal/adapter/http/shared/response"
+	jwtpkg "nexus/pkg/jwt"
+	"nexus/pkg/logger"
+	"nexus/pkg/uuidv7"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newTestJWTManager(opts ...jwtpkg.Option) *jwtpkg.JWTManager {
+	return jwtpkg.NewJWTManager("test-secret", 15*time.Minute, 24*time.Hour, opts...)
+}
+
+func accessToken(t *testing.T, m *jwtpkg.JWTManager, roles ...string) string {
+	t.Helper()
+
+	pair, err := m.GenerateTokenPairWithClaims(uuidv7.New(), "user@example.com", roles, nil)
+	if err != nil {
+		t.Fatalf("GenerateTokenPairWithClaims: %v", err)
+	}
+	return pair.AccessToken
+}
+
+func ok(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+func serveRequest(r *gin.Engine, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// Routes the default logger to a JSON buffer for the rest of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger.Init(logger.Config{Format: "json", Output: &buf})
+	t.Cleanup(func() { logger.Init(logger.Config{Output: io.Discard}) })
+	return &buf
+}
+
+// Decodes one JSON record per line
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("decode log record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestRequireAuthExcept(t *testing.T) {
+	auth := NewAuthMiddleware(newTestJWTManager())
+
+	r := gin.New()
+	r.Use(auth.RequireAuthExcept("/api/v1/webhooks"))
+	r.POST("/api/v1/webhooks", ok)
+	r.POST("/api/v1/webhooks/:id", ok)
+	r.POST("/api/v1/webhooks-admin/:id", ok)
+	r.GET("/api/v1/users", ok)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/api/v1/webhooks", http.StatusOK},
+		{"/api/v1/webhooks/github", http.StatusOK},
+		{"/api/v1/webhooks-admin/github", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		w := serveRequest(r, httptest.NewRequest(http.MethodPost, tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("POST %s without token: status = %d, want %d", tt.path, w.Code, tt.status)
+		}
+	}
+
+	if w := serveRequest(r, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)); w.Code != http.StatusUnauthorized {
+		t.Errorf("GET /api/v1/users without token: status = %d, want 401", w.Code)
+	}
+}
+
+func TestRequireAuthTokenSources(t *testing.T) {
+	m := newTestJWTManager()
+	auth := NewAuthMiddleware(m, WithTokenCookie("access_token"))
+	token := accessToken(t, m)
+
+	r := gin.New()
+	r.GET("/me", auth.RequireAuth(), ok)
+
+	tests := []struct {
+		name   string
+		header string
+		cookie string
+		status int
+	}{
+		{"header only", "Bearer " + token, "", http.StatusOK},
+		{"cookie only", "", token, http.StatusOK},
+		{"both", "Bearer " + token, "stale-token", http.StatusOK},
+		{"header takes precedence", "Bearer stale-token", token, http.StatusUnauthorized},
+		{"neither", "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "access_token", Value: tt.cookie})
+			}
+
+			if w := serveRequest(r, req); w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestRequireRoles(t *testing.T) {
+	m := newTestJWTManager()
+	auth := NewAuthMiddleware(m)
+
+	r := gin.New()
+	r.GET("/admin", auth.RequireAuth(), auth.RequireRoles("admin", "owner"), ok)
+	r.GET("/unguarded", auth.RequireRoles("admin"), ok)
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{"allowed", "/admin", accessToken(t, m, "user", "owner"), http.StatusOK},
+		{"missing role", "/admin", accessToken(t, m, "user"), http.StatusForbidden},
+		{"unauthenticated", "/admin", "", http.StatusUnauthorized},
+		{"without RequireAuth", "/unguarded", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			if w := serveRequest(r, req); w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestRequireAnyScope(t *testing.T) {
+	m := newTestJWTManager()
+	auth := NewAuthMiddleware(m)
+
+	r := gin.New()
+	r.GET("/reports", auth.RequireAuth(), auth.RequireAnyScope("reports:read"), ok)
+
+	pair, err := m.GenerateTokenPairWithClaims(uuidv7.New(), "user@example.com", nil, []string{"reports:read"})
+	if err != nil {
+		t.Fatalf("GenerateTokenPairWithClaims: %v", err)
+	}
+
+	for token, status := range map[string]int{
+		pair.AccessToken:  http.StatusOK,
+		accessToken(t, m): http.StatusForbidden,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/reports", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		if w := serveRequest(r, req); w.Code != status {
+			t.Errorf("status = %d, want %d", w.Code, status)
+		}
+	}
+}
+
+func TestHandlerLogsCarryRequestAndUserID(t *testing.T) {
+	logs := captureLogs(t)
+	m := newTestJWTManager()
+	auth := NewAuthMiddleware(m)
+
+	r := gin.New()
+	r.Use(RequestID())
+	r.GET("/me", auth.RequireAuth(), func(c *gin.Context) {
+		logger.InfoContext(c.Request.Context(), "loading profile")
+		c.Status(http.StatusOK)
+	})
+
+	userID := uuidv7.New()
+	pair, err := m.GenerateTokenPair(userID, "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+	req.Header.Set("X-Request-ID", "req-1")
+	serveRequest(r, req)
+
+	records := logRecords(t, logs)
+	if len(records) != 1 {
+		t.Fatalf("log records = %d, want 1", len(records))
+	}
+	if got := records[0]["user_id"]; got != userID.String() {
+		t.Errorf("user_id = %v, want %s", got, userID)
+	}
+	if got := records[0]["request_id"]; got != "req-1" {
+		t.Errorf("request_id = %v, want req-1", got)
+	}
+}
+
+func TestRequireAuthErrorCodes(t *testing.T) {
+	m := newTestJWTManager()
+	expired := jwtpkg.NewJWTManager("test-secret", -time.Minute, 24*time.Hour)
+	auth := NewAuthMiddleware(m)
+
+	r := gin.New()
+	r.GET("/me", auth.RequireAuth(), ok)
+
+	tests := []struct {
+		name  string
+		token string
+		code  string
+	}{
+		{"missing", "", response.CodeUnauthenticated},
+		{"expired", accessToken(t, expired), response.CodeTokenExpired},
+		{"malformed", "not.a.jwt", response.CodeTokenInvalid},
+		{"wrong signature", accessToken(t, jwtpkg.NewJWTManager("other-secret", time.Minute, time.Hour)), response.CodeTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := serveRequest(r, req)
+
+			var body response.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body %q: %v", w.Body.String(), err)
+			}
+			if w.Code != http.StatusUnauthorized || body.Code != tt.code {
+				t.Errorf("response = %d %q, want 401 %q", w.Code, body.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestRequireAuthWithQueryOnSSERoute(t *testing.T) {
+	m := newTestJWTManager()
+	auth := NewAuthMiddleware(m)
+	token := accessToken(t, m)
+
+	var seenQuery string
+	events := func(c *gin.Context) {
+		seenQuery = c.Request.URL.RawQuery
+		c.SSEvent("ready", "ok")
+	}
+
+	r := gin.New()
+	r.GET("/events", auth.RequireAuthWithQuery("access_token"), events)
+	r.GET("/events-header-only", auth.RequireAuth(), events)
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   int
+	}{
+		{"query token", "/events?access_token=" + token + "&topic=orders", "", http.StatusOK},
+		{"header takes precedence", "/events?access_token=" + token, "Bearer stale-token", http.StatusUnauthorized},
+		{"no token", "/events", "", http.StatusUnauthorized},
+		{"not opted in", "/events-header-only?access_token=" + token, "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seenQuery = ""
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept", "text/event-stream")
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			w := serveRequest(r, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			if !bytes.Contains(w.Body.Bytes(), []byte("event:ready")) {
+				t.Errorf("body = %q, want the ready event", w.Body.String())
+			}
+			// The token is stripped before the handler and request logs see the URL
+			if seenQuery != "topic=orders" {
+				t.Errorf("handler query = %q, want only topic=orders", seenQuery)
+			}
+		})
+	}
+}
+
+// TokenStore whose lookups block until release is closed
+type slowTokenStore struct {
+	*jwtpkg.MemoryTokenStore
+	release chan struct{}
+}
+
+func newSlowTokenStore(t *testing.T) *slowTokenStore {
+	s := &slowTokenStore{MemoryTokenStore: jwtpkg.NewMemoryTokenStore(), release: make(chan struct{})}
+	t.Cleanup(func() { close(s.release) })
+	return s
+}
+
+func (s *slowTokenStore) IsRevoked(jti string) (bool, error) {
+	<-s.release
+	return s.MemoryTokenStore.IsRevoked(jti)
+}
+
+// Same store, but able to give up when the validation context ends
+type slowContextTokenStore struct {
+	*slowTokenStore
+	ctxErr chan error
+}
+
+func (s *slowContextTokenStore) IsRevokedContext(ctx context.Context, jti string) (bool, error) {
+	select {
+	case <-s.release:
+		return s.MemoryTokenStore.IsRevoked(jti)
+	case <-ctx.Done():
+		s.ctxErr <- ctx.Err()
+		return false, ctx.Err()
+	}
+}
+
+func TestRequireAuthSlowTokenStoreTimesOut(t *testing.T) {
+	contextStore := &slowContextTokenStore{slowTokenStore: newSlowTokenStore(t), ctxErr: make(chan error, 1)}
+
+	tests := []struct {
+		name  string
+		store jwtpkg.TokenStore
+	}{
+		{"store without context", newSlowTokenStore(t)},
+		{"context-aware store", contextStore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestJWTManager(jwtpkg.WithTokenStore(tt.store))
+			auth := NewAuthMiddleware(m, WithValidationTimeout(20*time.Millisecond))
+
+			r := gin.New()
+			r.GET("/", auth.RequireAuth(), ok)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+accessToken(t, m))
+
+			start := time.Now()
+			w := serveRequest(r, req)
+
+			if w.Code != http.StatusServiceUnavailable {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("request took %v, want it bounded by the validation timeout", elapsed)
+			}
+		})
+	}
+
+	select {
+	case err := <-contextStore.ctxErr:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("store ctx err = %v, want %v", err, context.DeadlineExceeded)
+		}
+	default:
+		t.Error("context-aware store did not see the deadline")
+	}
+}
@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"nexus/internal/infrastructure/config"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// Gzips responses for clients sending Accept-Encoding: gzip, when the body
+// reaches cfg.MinSize and its Content-Type is in cfg.ContentTypes.
+// Bodies are buffered until MinSize is reached, so small responses go out as is.
+// ETags from response.JSONWithETag are weak and hashed from the data,
+// so they stay valid for both encodings
+func Compress(cfg config.CompressionSection) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !acceptsGzip(c.GetHeader("Accept-Encoding")) || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		w := &gzipResponseWriter{ResponseWriter: c.Writer, cfg: cfg}
+		c.Writer = w
+		defer func() {
+			w.finish()
+			c.Writer = w.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.TrimSpace(coding) != "gzip" {
+			continue
+		}
+		// gzip;q=0 explicitly refuses it
+		return !zeroQuality(params)
+	}
+	return false
+}
+
+// Reports whether the parameters carry q=0 (or 0.0, 0.000)
+func zeroQuality(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return err == nil && q == 0
+	}
+	return false
+}
+
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	cfg      config.CompressionSection
+	status   int
+	buf      bytes.Buffer
+	decided  bool
+	compress bool
+	gz       *gzip.Writer
+}
+
+// Held back until we know whether the body is compressed
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+}
+
+// Called for body-less responses (AbortWithStatus, 204), nothing left to compress
+func (w *gzipResponseWriter) WriteHeaderNow() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *gzipResponseWriter) Status() int {
+	if !w.decided && w.status != 0 {
+		return w.status
+	}
+	return w.ResponseWriter.Status()
+}
+
+// Like gin, a status set with WriteHeader alone doesn't count as written
+func (w *gzipResponseWriter) Written() bool {
+	return w.buf.Len() > 0 || w.ResponseWriter.Written()
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buf.Write(data)
+		if w.buf.Len() < w.cfg.MinSize {
+			return len(data), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+
+	if w.compress {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Streaming responses (SSE) can't wait for MinSize
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(w.buf.Len() >= w.cfg.MinSize)
+	}
+	if w.compress {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Writes the held back status and buffered body, compressed when allowed
+func (w *gzipResponseWriter) decide(largeEnough bool) error {
+	w.decided = true
+
+	header := w.Header()
+	header.Add("Vary", "Accept-Encoding")
+	w.compress = largeEnough && header.Get("Content-Encoding") == "" && w.compressible(header.Get("Content-Type"))
+
+	if w.compress {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+
+		w.gz = gzipWriterPool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+
+	var err error
+	if w.compress {
+		_, err = w.gz.Write(w.buf.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+	w.buf.Reset()
+	return err
+}
+
+func (w *gzipResponseWriter) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(w.cfg.ContentTypes, mediaType)
+}
+
+func (w *gzipResponseWriter) finish() {
+	if !w.decided {
+		// Small body, or none at all (304, 204, HEAD)
+		if w.buf.Len() == 0 && w.status != 0 && !bodyAllowed(w.status) {
+			w.decided = true
+			w.ResponseWriter.WriteHeader(w.status)
+			return
+		}
+		_ = w.decide(false)
+	}
+
+	if w.compress {
+		_ = w.gz.Close()
+		w.gz.Reset(nil)
+		gzipWriterPool.Put(w.gz)
+		w.gz = nil
+	}
+}
+
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
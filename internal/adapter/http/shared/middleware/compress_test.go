@@ -0,0 +1,253 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"nexus/internal/adapter/http/shared/response"
+	"nexus/internal/infrastructure/config"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+var testCompression = config.CompressionSection{
+	Enabled:      true,
+	MinSize:      1024,
+	ContentTypes: []string{"application/json"},
+}
+
+func compressRequest(handler gin.HandlerFunc, acceptEncoding string, setup ...func(*http.Request)) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(Compress(testCompression))
+	r.GET("/", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	for _, fn := range setup {
+		fn(req)
+	}
+
+	return serveRequest(r, req)
+}
+
+func jsonItems(n int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items := make([]string, n)
+		for i := range items {
+			items[i] = "item"
+		}
+		c.JSON(http.StatusOK, gin.H{"items": items})
+	}
+}
+
+func gunzip(t *testing.T, body io.Reader) string {
+	t.Helper()
+
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		t.Fatalf("body is not gzip: %v", err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("read gzip body: %v", err)
+	}
+	return string(data)
+}
+
+func TestCompressLargeJSON(t *testing.T) {
+	w := compressRequest(jsonItems(1000), "gzip, deflate")
+
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("Vary = %q, want Accept-Encoding", got)
+	}
+	if body := gunzip(t, w.Body); !strings.HasPrefix(body, `{"items":["item"`) {
+		t.Errorf("decompressed body = %.40q...", body)
+	}
+}
+
+func TestCompressSkips(t *testing.T) {
+	image := func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", make([]byte, 4096))
+	}
+
+	tests := []struct {
+		name           string
+		handler        gin.HandlerFunc
+		acceptEncoding string
+	}{
+		{"small JSON", jsonItems(2), "gzip"},
+		{"content type not listed", image, "gzip"},
+		{"no Accept-Encoding", jsonItems(1000), ""},
+		{"other encoding", jsonItems(1000), "br"},
+		{"gzip refused with q=0", jsonItems(1000), "br, gzip;q=0"},
+		{"gzip refused with q=0.0", jsonItems(1000), "gzip; q=0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := compressRequest(tt.handler, tt.acceptEncoding)
+
+			if got := w.Header().Get("Content-Encoding"); got != "" {
+				t.Errorf("Content-Encoding = %q, want none", got)
+			}
+			if w.Code != http.StatusOK || w.Body.Len() == 0 {
+				t.Errorf("status = %d, body length = %d", w.Code, w.Body.Len())
+			}
+		})
+	}
+}
+
+func TestCompressAcceptsNonZeroQuality(t *testing.T) {
+	w := compressRequest(jsonItems(1000), "gzip;q=0.5")
+
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", got)
+	}
+}
+
+func TestCompressBodylessResponses(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		setup   func(*http.Request)
+		status  int
+	}{
+		{
+			name:    "status only",
+			handler: func(c *gin.Context) { c.Status(http.StatusNoContent) },
+			status:  http.StatusNoContent,
+		},
+		{
+			name:    "aborted",
+			handler: func(c *gin.Context) { c.AbortWithStatus(http.StatusNoContent) },
+			status:  http.StatusNoContent,
+		},
+		{
+			name:    "etag not modified",
+			handler: func(c *gin.Context) { response.JSONWithETag(c, http.StatusOK, []string{"a", "b"}) },
+			setup:   func(r *http.Request) { r.Header.Set("If-None-Match", "*") },
+			status:  http.StatusNotModified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var setup []func(*http.Request)
+			if tt.setup != nil {
+				setup = append(setup, tt.setup)
+			}
+			w := compressRequest(tt.handler, "gzip", setup...)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if w.Body.Len() != 0 {
+				t.Errorf("body = %q, want empty", w.Body.String())
+			}
+			if got := w.Header().Get("Content-Encoding"); got != "" {
+				t.Errorf("Content-Encoding = %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestCompressETagSameForBothEncodings(t *testing.T) {
+	handler := func(c *gin.Context) {
+		response.JSONWithETag(c, http.StatusOK, strings.Split(strings.Repeat("item,", 500), ","))
+	}
+
+	compressed := compressRequest(handler, "gzip")
+	plain := compressRequest(handler, "")
+
+	if compressed.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatal("response was not compressed")
+	}
+	if got, want := compressed.Header().Get("ETag"), plain.Header().Get("ETag"); got == "" || got != want {
+		t.Errorf("ETag = %q compressed, %q plain, want equal", got, want)
+	}
+}
+
+func TestCompressStatusBeforeBody(t *testing.T) {
+	var written bool
+	var status int
+
+	w := compressRequest(func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+		written, status = c.Writer.Written(), c.Writer.Status()
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	}, "gzip")
+
+	if written {
+		t.Error("Written() = true after WriteHeader alone")
+	}
+	if status != http.StatusCreated {
+		t.Errorf("Status() = %d, want %d", status, http.StatusCreated)
+	}
+	if w.Code != http.StatusCreated {
+		t.Errorf("response status = %d, want %d", w.Code, http.StatusCreated)
+	}
+}
+
+func TestCompressFlushedStream(t *testing.T) {
+	var flushedBeforeEnd bool
+	var recorder *httptest.ResponseRecorder
+
+	r := gin.New()
+	r.Use(Compress(testCompression))
+	r.GET("/events", func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+		c.Writer.WriteString(`{"event":"ping"}` + "\n")
+		c.Writer.Flush()
+		flushedBeforeEnd = recorder.Flushed && strings.Contains(recorder.Body.String(), "ping")
+
+		c.Writer.WriteString(strings.Repeat(`{"event":"data"}`+"\n", 200))
+		c.Writer.Flush()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	recorder = httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+
+	if !flushedBeforeEnd {
+		t.Error("first event was held back instead of flushed")
+	}
+	// Decided on the first flush, below MinSize, so the stream stays uncompressed
+	if got := recorder.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none", got)
+	}
+	if got := strings.Count(recorder.Body.String(), "\n"); got != 201 {
+		t.Errorf("events received = %d, want 201", got)
+	}
+}
+
+func TestCompressFlushedLargeStream(t *testing.T) {
+	r := gin.New()
+	r.Use(Compress(testCompression))
+	r.GET("/events", func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+		for range 3 {
+			c.Writer.WriteString(strings.Repeat(`{"event":"data"}`+"\n", 100))
+			c.Writer.Flush()
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := serveRequest(r, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if got := strings.Count(gunzip(t, w.Body), "\n"); got != 300 {
+		t.Errorf("events received = %d, want 300", got)
+	}
+}
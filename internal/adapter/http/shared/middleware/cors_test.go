@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"nexus/internal/infrastructure/config"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var testCORS = config.CORSSection{
+	AllowedOrigins:   []string{"https://app.example.com"},
+	AllowedMethods:   []string{"GET", "POST"},
+	AllowedHeaders:   []string{"Authorization", "Content-Type"},
+	ExposedHeaders:   []string{"X-Request-ID"},
+	AllowCredentials: true,
+	MaxAge:           12 * time.Hour,
+}
+
+func corsRequest(cfg config.CORSSection, method, origin string, setup ...func(*http.Request)) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(CORS(cfg))
+	r.GET("/items", ok)
+	r.OPTIONS("/items", ok)
+
+	req := httptest.NewRequest(method, "/items", nil)
+	req.Header.Set("Origin", origin)
+	for _, fn := range setup {
+		fn(req)
+	}
+	return serveRequest(r, req)
+}
+
+func TestCORSAllowedOrigin(t *testing.T) {
+	w := corsRequest(testCORS, http.MethodGet, "https://app.example.com")
+
+	want := map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example.com",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Expose-Headers":    "X-Request-ID",
+		"Vary":                             "Origin",
+	}
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	w := corsRequest(testCORS, http.MethodGet, "https://evil.example.com")
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+	}
+	// Served, the browser blocks reading the response
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	preflight := func(r *http.Request) {
+		r.Header.Set("Access-Control-Request-Method", "POST")
+	}
+
+	w := corsRequest(testCORS, http.MethodOptions, "https://app.example.com", preflight)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", w.Code)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":  "https://app.example.com",
+		"Access-Control-Allow-Methods": "GET, POST",
+		"Access-Control-Allow-Headers": "Authorization, Content-Type",
+		"Access-Control-Max-Age":       "43200",
+	}
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+
+	if w := corsRequest(testCORS, http.MethodOptions, "https://evil.example.com", preflight); w.Code != http.StatusForbidden {
+		t.Errorf("preflight from disallowed origin: status = %d, want 403", w.Code)
+	}
+}
+
+func TestCORSWildcard(t *testing.T) {
+	cfg := config.CORSSection{AllowedOrigins: []string{"*"}}
+
+	w := corsRequest(cfg, http.MethodGet, "https://anyone.example.com")
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+}
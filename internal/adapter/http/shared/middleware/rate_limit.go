@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"nexus/internal/adapter/http/shared/response"
+	"nexus/pkg/logger"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const retryAfterHeader = "Retry-After"
+
+// Token bucket storage, implement it to share limits between instances (e.g. Redis)
+type RateLimitStore interface {
+	// Takes one token from the bucket for key. When the bucket is empty returns
+	// false and how long until the next token is available
+	Allow(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error)
+}
+
+// Limits requests per authenticated user
+type RateLimiter struct {
+	store RateLimitStore
+	rate  float64 // Tokens added per second
+	burst int
+}
+
+type RateLimitOption func(*RateLimiter)
+
+func WithRateLimitStore(store RateLimitStore) RateLimitOption {
+	return func(l *RateLimiter) {
+		l.store = store
+	}
+}
+
+// Allows burst requests at once, refilled at rate requests per second.
+// Uses an in-memory store unless WithRateLimitStore is given
+func NewRateLimiter(rate float64, burst int, opts ...RateLimitOption) *RateLimiter {
+	l := &RateLimiter{
+		rate:  rate,
+		burst: burst,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if l.store == nil {
+		l.store = NewMemoryRateLimitStore()
+	}
+
+	return l
+}
+
+// Limits requests per user ID. Must run after RequireAuth
+func (l *RateLimiter) PerUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := GetUserID(c)
+		if !ok {
+			response.Error(c, http.StatusUnauthorized, "authentication required", nil)
+			c.Abort()
+			return
+		}
+
+		allowed, retryAfter, err := l.store.Allow(c.Request.Context(), "user:"+userID.String(), l.rate, l.burst)
+		if err != nil {
+			// Fail open, an unavailable store shouldn't take the API down
+			logger.FromContext(c.Request.Context()).Warn("Rate limit check failed", "error", err)
+			c.Next()
+			return
+		}
+
+		if !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			c.Header(retryAfterHeader, strconv.Itoa(max(seconds, 1)))
+			response.Error(c, http.StatusTooManyRequests, "rate limit exceeded", nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastFill time.Time
+}
+
+// In-memory RateLimitStore, suitable for a single instance or tests.
+// Buckets are evicted once they would have refilled completely
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+const rateLimitSweepInterval = time.Minute
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryRateLimitStore) Allow(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= rateLimitSweepInterval {
+		s.evictFull(now, rate, burst)
+		s.lastSweep = now
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(burst), lastFill: now}
+		s.buckets[key] = bucket
+	}
+
+	bucket.tokens = refill(bucket, now, rate, burst)
+	bucket.lastFill = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0, nil
+	}
+
+	if rate <= 0 {
+		return false, time.Duration(math.MaxInt64), nil
+	}
+
+	wait := time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+	return false, wait, nil
+}
+
+func refill(bucket *tokenBucket, now time.Time, rate float64, burst int) float64 {
+	elapsed := now.Sub(bucket.lastFill).Seconds()
+	return math.Min(float64(burst), bucket.tokens+elapsed*rate)
+}
+
+// Must be called with mu held
+func (s *MemoryRateLimitStore) evictFull(now time.Time, rate float64, burst int) {
+	for key, bucket := range s.buckets {
+		if refill(bucket, now, rate, burst) >= float64(burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
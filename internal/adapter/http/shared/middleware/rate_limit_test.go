@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Returns a GET / requester behind RequireAuth and limiter
+func rateLimitedGet(limiter *RateLimiter) func(token string) *httptest.ResponseRecorder {
+	auth := NewAuthMiddleware(newTestJWTManager())
+	r := gin.New()
+	r.GET("/", auth.RequireAuth(), limiter.PerUser(), ok)
+
+	get := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return serveRequest(r, req)
+	}
+	return get
+}
+
+func TestPerUserRejectsRequestPastBurst(t *testing.T) {
+	m := newTestJWTManager()
+	get := rateLimitedGet(NewRateLimiter(0.5, 3))
+	token := accessToken(t, m)
+
+	for i := 1; i <= 3; i++ {
+		if w := get(token); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want %d", i, w.Code, http.StatusOK)
+		}
+	}
+
+	w := get(token)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request 4: status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get(retryAfterHeader); got != "2" {
+		t.Errorf("%s = %q, want %q", retryAfterHeader, got, "2")
+	}
+
+	// Other users have their own bucket
+	if w := get(accessToken(t, m)); w.Code != http.StatusOK {
+		t.Errorf("other user: status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestPerUserRequiresAuth(t *testing.T) {
+	r := gin.New()
+	r.GET("/", NewRateLimiter(1, 1).PerUser(), ok)
+
+	w := serveRequest(r, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Allow(context.Context, string, float64, int) (bool, time.Duration, error) {
+	return false, 0, errors.New("store unavailable")
+}
+
+func TestPerUserFailsOpenOnStoreError(t *testing.T) {
+	get := rateLimitedGet(NewRateLimiter(1, 1, WithRateLimitStore(failingRateLimitStore{})))
+	token := accessToken(t, newTestJWTManager())
+
+	for i := 1; i <= 3; i++ {
+		if w := get(token); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want %d", i, w.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestMemoryRateLimitStoreRefills(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 1; i <= 2; i++ {
+		if allowed, _, _ := store.Allow(ctx, "user:1", 1, 2); !allowed {
+			t.Fatalf("request %d rejected within burst", i)
+		}
+	}
+
+	allowed, retryAfter, _ := store.Allow(ctx, "user:1", 1, 2)
+	if allowed || retryAfter != time.Second {
+		t.Fatalf("Allow = %v, %v, want false, 1s", allowed, retryAfter)
+	}
+
+	now = now.Add(time.Second)
+	if allowed, _, _ := store.Allow(ctx, "user:1", 1, 2); !allowed {
+		t.Error("request rejected after a token was refilled")
+	}
+}
+
+func TestMemoryRateLimitStoreEvictsFullBuckets(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	store.Allow(ctx, "user:1", 1, 2)
+	now = now.Add(rateLimitSweepInterval)
+	store.Allow(ctx, "user:2", 1, 2)
+
+	if _, ok := store.buckets["user:1"]; ok {
+		t.Error("refilled bucket was not evicted")
+	}
+	if _, ok := store.buckets["user:2"]; !ok {
+		t.Error("active bucket was evicted")
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"nexus/internal/adapter/http/shared/response"
+	"nexus/pkg/logger"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Turns handler panics into a logged 500. The panic value is only returned
+// to the client when exposeErrors is set (never enable it in production)
+func Recovery(exposeErrors bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			// Client went away, nothing to respond to
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			logger.ErrorContext(c.Request.Context(), "Panic recovered",
+				slog.Any("panic", rec),
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.Request.URL.Path),
+				slog.String("stack", string(debug.Stack())))
+
+			var err error
+			if exposeErrors {
+				err = fmt.Errorf("panic: %v", rec)
+			}
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+
+			response.ErrorWithCode(c, http.StatusInternalServerError, response.CodeInternal, "internal server error", err)
+			c.Abort()
+		}()
+
+		c.Next()
+	}
+}
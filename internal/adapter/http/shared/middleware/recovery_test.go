@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"nexus/internal/adapter/http/shared/response"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func panicking(c *gin.Context) {
+	panic("nil map write in order service")
+}
+
+func TestRecoveryReturnsClean500(t *testing.T) {
+	logs := captureLogs(t)
+
+	r := gin.New()
+	r.Use(Recovery(false))
+	r.GET("/", panicking)
+
+	w := serveRequest(r, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	var body response.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body is not a JSON envelope: %q", w.Body.String())
+	}
+	if body.Success || body.Code != response.CodeInternal {
+		t.Errorf("body = %+v, want a failed envelope with %q", body, response.CodeInternal)
+	}
+	if strings.Contains(w.Body.String(), "nil map") || strings.Contains(w.Body.String(), "goroutine") {
+		t.Errorf("panic details leaked to the client: %s", w.Body.String())
+	}
+
+	records := logRecords(t, logs)
+	if len(records) != 1 || records[0]["panic"] != "nil map write in order service" || records[0]["stack"] == "" {
+		t.Errorf("log records = %v, want the panic with its stack", records)
+	}
+}
+
+func TestRecoveryExposeErrors(t *testing.T) {
+	captureLogs(t)
+
+	r := gin.New()
+	r.Use(Recovery(true))
+	r.GET("/", panicking)
+
+	w := serveRequest(r, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !strings.Contains(w.Body.String(), "nil map write") {
+		t.Errorf("body = %s, want the panic value", w.Body.String())
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"nexus/pkg/logger"
+	"nexus/pkg/uuidv7"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+// Reuses the client's X-Request-ID or generates a new one, and exposes it
+// on the gin context, the request context (for logger.FromContext) and the response
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = uuidv7.New().String()
+		}
+
+		c.Set(string(logger.RequestIDKey), requestID)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), logger.RequestIDKey, requestID))
+		c.Header(requestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+// Rejects empty, oversized or non-printable IDs so they can't pollute logs
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"nexus/pkg/logger"
+	"nexus/pkg/uuidv7"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		supplied string
+		reused   bool
+	}{
+		{"generated", "", false},
+		{"reused", "client-req-42", true},
+		{"invalid replaced", "has spaces\tand tabs", false},
+		{"oversized replaced", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			r := gin.New()
+			r.Use(RequestID())
+			r.GET("/", func(c *gin.Context) {
+				seen, _ = c.Request.Context().Value(logger.RequestIDKey).(string)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.supplied != "" {
+				req.Header.Set("X-Request-ID", tt.supplied)
+			}
+			id := serveRequest(r, req).Header().Get("X-Request-ID")
+
+			if tt.reused {
+				if id != tt.supplied {
+					t.Errorf("X-Request-ID = %q, want the supplied %q", id, tt.supplied)
+				}
+			} else if _, err := uuidv7.ParseV7(id); err != nil {
+				t.Errorf("X-Request-ID = %q, want a generated uuidv7", id)
+			}
+			if seen != id {
+				t.Errorf("request context ID = %q, want %q", seen, id)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"nexus/internal/adapter/http/shared/response"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sets a deadline on the request context, e.g. group.Use(middleware.Timeout(5*time.Second)).
+// Context-aware work (DB queries, outgoing calls) is cancelled when it passes,
+// and a 503 is sent if the handler hasn't written a response by then. Handlers
+// passing the cancelled work's error to response.RespondError get the same 503.
+// The handler itself is not interrupted, it has to honor ctx.Done()
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			response.ErrorWithCode(c, http.StatusServiceUnavailable, response.CodeTimeout, "request timed out", nil)
+			c.Abort()
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"nexus/internal/adapter/http/shared/response"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeoutSlowHandler(t *testing.T) {
+	var handlerErr error
+
+	r := gin.New()
+	r.Use(Timeout(20 * time.Millisecond))
+	r.GET("/report", func(c *gin.Context) {
+		// Stands in for a query that honors ctx
+		select {
+		case <-time.After(time.Second):
+			c.Status(http.StatusOK)
+		case <-c.Request.Context().Done():
+			handlerErr = c.Request.Context().Err()
+		}
+	})
+
+	start := time.Now()
+	w := serveRequest(r, httptest.NewRequest(http.MethodGet, "/report", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+	if !errors.Is(handlerErr, context.DeadlineExceeded) {
+		t.Errorf("handler context err = %v, want %v", handlerErr, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("request took %v, want about the timeout", elapsed)
+	}
+}
+
+// Stands in for a repository call that honors ctx, like a sqlx query
+func slowQuery(ctx context.Context) error {
+	select {
+	case <-time.After(time.Second):
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("load report: %w", ctx.Err())
+	}
+}
+
+func TestTimeoutHandlerRespondingWithError(t *testing.T) {
+	r := gin.New()
+	r.Use(Timeout(20 * time.Millisecond))
+	r.GET("/report", func(c *gin.Context) {
+		if err := slowQuery(c.Request.Context()); err != nil {
+			response.RespondError(c, err)
+			return
+		}
+		response.Success(c, http.StatusOK, "report")
+	})
+
+	start := time.Now()
+	w := serveRequest(r, httptest.NewRequest(http.MethodGet, "/report", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", w.Code)
+	}
+	var body response.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body %q: %v", w.Body.String(), err)
+	}
+	if body.Code != response.CodeTimeout {
+		t.Errorf("code = %q, want %q", body.Code, response.CodeTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("request took %v, want about the timeout", elapsed)
+	}
+}
+
+func TestTimeoutKeepsWrittenResponse(t *testing.T) {
+	r := gin.New()
+	r.Use(Timeout(10 * time.Millisecond))
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "done")
+		time.Sleep(30 * time.Millisecond)
+	})
+
+	w := serveRequest(r, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "done" {
+		t.Errorf("response = %d %q, want the handler's 200", w.Code, w.Body.String())
+	}
+}
+
+func TestTimeoutFastHandler(t *testing.T) {
+	r := gin.New()
+	r.Use(Timeout(time.Second))
+	r.GET("/", ok)
+
+	if w := serveRequest(r, httptest.NewRequest(http.MethodGet, "/", nil)); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
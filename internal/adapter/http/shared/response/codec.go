@@ -0,0 +1,21 @@
+package response
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+// Writes obj as MessagePack when the Accept header asks for it, JSON otherwise
+// (including a missing or unsupported Accept header)
+func negotiate(c *gin.Context, status int, obj any) {
+	c.Header("Vary", "Accept")
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEMSGPACK, binding.MIMEMSGPACK2) {
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		// Field names come from the json tags
+		c.Render(status, render.MsgPack{Data: obj})
+	default:
+		c.JSON(status, obj)
+	}
+}
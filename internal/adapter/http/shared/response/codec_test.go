@@ -0,0 +1,61 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		decode      func([]byte, any) error
+	}{
+		{"no accept header", "", "application/json; charset=utf-8", json.Unmarshal},
+		{"json", "application/json", "application/json; charset=utf-8", json.Unmarshal},
+		{"msgpack", "application/msgpack", "application/msgpack; charset=utf-8", binding.MsgPack.BindBody},
+		{"x-msgpack", "application/x-msgpack", "application/msgpack; charset=utf-8", binding.MsgPack.BindBody},
+		{"unsupported", "text/html", "application/json; charset=utf-8", json.Unmarshal},
+	}
+
+	handlers := map[string]gin.HandlerFunc{
+		"success": func(c *gin.Context) { Success(c, http.StatusOK, map[string]any{"name": "widget"}) },
+		"error":   func(c *gin.Context) { Error(c, http.StatusBadRequest, "bad request", nil) },
+	}
+
+	for _, tt := range tests {
+		for kind, handler := range handlers {
+			t.Run(tt.name+"/"+kind, func(t *testing.T) {
+				w := serve(handler, func(req *http.Request) {
+					if tt.accept != "" {
+						req.Header.Set("Accept", tt.accept)
+					}
+				})
+
+				if got := w.Header().Get("Content-Type"); got != tt.contentType {
+					t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+				}
+				if got := w.Header().Get("Vary"); got != "Accept" {
+					t.Errorf("Vary = %q, want Accept", got)
+				}
+
+				// Field names come from the json tags in both encodings
+				var body Response
+				if err := tt.decode(w.Body.Bytes(), &body); err != nil {
+					t.Fatalf("decode body: %v", err)
+				}
+				if body.Success != (kind == "success") || body.Timestamp == 0 {
+					t.Errorf("body = %+v, want a %s envelope", body, kind)
+				}
+				if kind == "error" && body.Message != "bad request" {
+					t.Errorf("message = %q, want %q", body.Message, "bad request")
+				}
+			})
+		}
+	}
+}
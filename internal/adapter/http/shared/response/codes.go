@@ -0,0 +1,16 @@
+package response
+
+// Machine-readable error codes, stable across releases
+const (
+	CodeUnauthenticated  = "UNAUTHENTICATED"
+	CodeTokenExpired     = "TOKEN_EXPIRED" // Client should refresh the token
+	CodeTokenInvalid     = "TOKEN_INVALID" // Client should log in again
+	CodeForbidden        = "FORBIDDEN"
+	CodeNotFound         = "NOT_FOUND"
+	CodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+	CodeConflict         = "CONFLICT"
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeBadRequest       = "BAD_REQUEST"
+	CodeInternal         = "INTERNAL_ERROR"
+	CodeTimeout          = "TIMEOUT" // Deadline passed, the client may retry
+)
@@ -0,0 +1,59 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"nexus/pkg/apperrors"
+	jwtpkg "nexus/pkg/jwt"
+	"nexus/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type errorMapping struct {
+	target error
+	status int
+	code   string
+}
+
+// Checked in order, the first match wins
+var errorMappings = []errorMapping{
+	{apperrors.ErrNotFound, http.StatusNotFound, CodeNotFound},
+	{apperrors.ErrConflict, http.StatusConflict, CodeConflict},
+	{apperrors.ErrValidation, http.StatusUnprocessableEntity, CodeValidationFailed},
+	{apperrors.ErrBadRequest, http.StatusBadRequest, CodeBadRequest},
+	{apperrors.ErrUnauthenticated, http.StatusUnauthorized, CodeUnauthenticated},
+	{apperrors.ErrForbidden, http.StatusForbidden, CodeForbidden},
+	{jwtpkg.ErrExpiredToken, http.StatusUnauthorized, CodeTokenExpired},
+	{jwtpkg.ErrInvalidToken, http.StatusUnauthorized, CodeTokenInvalid},
+	{jwtpkg.ErrRevokedToken, http.StatusUnauthorized, CodeTokenInvalid},
+	{jwtpkg.ErrRefreshTokenReused, http.StatusUnauthorized, CodeTokenInvalid},
+	// Work cancelled by the Timeout middleware's deadline
+	{context.DeadlineExceeded, http.StatusServiceUnavailable, CodeTimeout},
+}
+
+// Picks status and code from the error, so handlers can just
+// `response.RespondError(c, err)`. Unknown errors become 500
+func RespondError(c *gin.Context, err error) {
+	if fieldErrs := FieldErrorsFromValidator(err); fieldErrs != nil {
+		ValidationError(c, fieldErrs)
+		return
+	}
+
+	for _, m := range errorMappings {
+		if errors.Is(err, m.target) {
+			ErrorWithCode(c, m.status, m.code, err.Error(), nil)
+			return
+		}
+	}
+
+	// Driver and SQL messages stay in the logs, clients match them by request ID
+	logger.ErrorContext(c.Request.Context(), "Unhandled handler error",
+		slog.Any("error", err),
+		slog.String("method", c.Request.Method),
+		slog.String("path", c.Request.URL.Path))
+
+	ErrorWithCode(c, http.StatusInternalServerError, CodeInternal, "internal server error", nil)
+}
@@ -0,0 +1,67 @@
+package response
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"nexus/pkg/apperrors"
+	jwtpkg "nexus/pkg/jwt"
+	"nexus/pkg/logger"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRespondErrorMapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", fmt.Errorf("user 42: %w", apperrors.ErrNotFound), http.StatusNotFound, CodeNotFound},
+		{"conflict", fmt.Errorf("email taken: %w", apperrors.ErrConflict), http.StatusConflict, CodeConflict},
+		{"forbidden", apperrors.ErrForbidden, http.StatusForbidden, CodeForbidden},
+		{"expired token", jwtpkg.ErrExpiredToken, http.StatusUnauthorized, CodeTokenExpired},
+		{"reused refresh token", jwtpkg.ErrRefreshTokenReused, http.StatusUnauthorized, CodeTokenInvalid},
+		{"deadline exceeded", fmt.Errorf("load report: %w", context.DeadlineExceeded), http.StatusServiceUnavailable, CodeTimeout},
+		{"unknown", errors.New("boom"), http.StatusInternalServerError, CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(func(c *gin.Context) {
+				RespondError(c, tt.err)
+			})
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if body := decodeResponse(t, w); body.Code != tt.code || body.Success {
+				t.Errorf("code = %q, success = %v, want %q and false", body.Code, body.Success, tt.code)
+			}
+		})
+	}
+}
+
+func TestRespondErrorHidesInternalErrors(t *testing.T) {
+	var logs bytes.Buffer
+	logger.Init(logger.Config{Format: "json", Output: &logs})
+	t.Cleanup(func() { logger.Init(logger.Config{Output: io.Discard}) })
+
+	internal := errors.New(`pq: relation "users" does not exist`)
+	w := serve(func(c *gin.Context) {
+		RespondError(c, internal)
+	})
+
+	if strings.Contains(w.Body.String(), "users") {
+		t.Errorf("internal error leaked to the client: %s", w.Body.String())
+	}
+	if !strings.Contains(logs.String(), `relation \"users\" does not exist`) {
+		t.Errorf("internal error not logged: %s", logs.String())
+	}
+}
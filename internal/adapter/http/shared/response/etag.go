@@ -0,0 +1,51 @@
+package response
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Responds like Success but with a weak ETag computed from data.
+// Returns 304 without body when If-None-Match matches on GET/HEAD requests
+func JSONWithETag(c *gin.Context, code int, data any) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "failed to encode response", err)
+		return
+	}
+
+	// Hash data only: envelope timestamp changes on every response
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+
+	method := c.Request.Method
+	if (method == http.MethodGet || method == http.MethodHead) && etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	Success(c, code, data)
+}
+
+// Weak comparison as required for If-None-Match (RFC 9110)
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
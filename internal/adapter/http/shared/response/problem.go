@@ -0,0 +1,52 @@
+package response
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const problemContentType = "application/problem+json"
+
+// RFC 7807 problem details
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// Writes error as application/problem+json
+func Problem(c *gin.Context, status int, problem ProblemDetails) {
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(status)
+	}
+	if problem.Instance == "" {
+		problem.Instance = c.Request.URL.Path
+	}
+	problem.Status = status
+
+	c.Header("Content-Type", problemContentType)
+	c.JSON(status, problem)
+}
+
+// Converts an error Response into problem details, so handlers can opt in gradually
+func ProblemFromResponse(status int, resp Response) ProblemDetails {
+	detail := resp.Error
+	if detail == "" {
+		detail = resp.Message
+	} else if resp.Message != "" {
+		detail = resp.Message + ": " + detail
+	}
+
+	return ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
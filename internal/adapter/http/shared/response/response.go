@@ -1,18 +1,34 @@
 package response
 
 import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"nexus/pkg/logger"
+	"nexus/pkg/uuidv7"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 type Response struct {
-	Success   bool   `json:"success"`
-	Message   string `json:"message,omitempty"`
-	Data      any    `json:"data,omitempty"`
-	Error     string `json:"error,omitempty"`
-	Timestamp int64  `json:"timestamp"`
+	Success   bool         `json:"success"`
+	Message   string       `json:"message,omitempty"`
+	Data      any          `json:"data,omitempty"`
+	Code      string       `json:"code,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	Meta      *Meta        `json:"meta,omitempty"`
+	Timestamp int64        `json:"timestamp"`
+}
+
+// Request-scoped metadata echoed back for support debugging
+type Meta struct {
+	RequestID string `json:"request_id,omitempty"`
+	TraceID   string `json:"trace_id,omitempty"`
 }
 
 type PaginatedResponse struct {
@@ -21,17 +37,48 @@ type PaginatedResponse struct {
 	PageSize   int   `json:"page_size"`
 	Total      int   `json:"total"`
 	TotalPages int   `json:"total_pages"`
+	Meta       *Meta `json:"meta,omitempty"`
 	Timestamp  int64 `json:"timestamp"`
 }
 
+// Keyset pagination over time-ordered uuidv7 keys
+type CursorPaginatedResponse struct {
+	Items      any    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
 func Success(c *gin.Context, code int, data any) {
-	c.JSON(code, Response{
+	SuccessWithMessage(c, code, "", data)
+}
+
+// Success envelope with a human-readable note, e.g. "password updated"
+func SuccessWithMessage(c *gin.Context, code int, message string, data any) {
+	negotiate(c, code, Response{
 		Success:   true,
+		Message:   message,
 		Data:      data,
+		Meta:      MetaFromContext(c),
 		Timestamp: time.Now().Unix(),
 	})
 }
 
+// 201 with the success envelope, for newly created resources
+func Created(c *gin.Context, data any) {
+	Success(c, http.StatusCreated, data)
+}
+
+// 202 with the success envelope, for work queued to be processed later
+func Accepted(c *gin.Context, data any) {
+	Success(c, http.StatusAccepted, data)
+}
+
+// 204 with an empty body (no envelope)
+func NoContent(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+}
+
 type ErrorResponse struct {
 	Success   bool   `json:"success" example:"false"`
 	Message   string `json:"message" example:"Error message"`
@@ -47,15 +94,57 @@ type SuccessResponse struct {
 }
 
 func Error(c *gin.Context, code int, message string, err error) {
+	ErrorWithCode(c, code, "", message, err)
+}
+
+// Error with a stable machine-readable code clients can branch on
+func ErrorWithCode(c *gin.Context, status int, code, message string, err error) {
 	errMsg := ""
 	if err != nil {
 		errMsg = err.Error()
 	}
 
-	c.JSON(code, Response{
+	negotiate(c, status, Response{
 		Success:   false,
 		Message:   message,
+		Code:      code,
 		Error:     errMsg,
+		Meta:      MetaFromContext(c),
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// Replaces gin's plain-text 404, e.g. r.NoRoute(response.NoRoute)
+func NoRoute(c *gin.Context) {
+	ErrorWithCode(c, http.StatusNotFound, CodeNotFound, "route not found", nil)
+}
+
+// Replaces gin's plain-text 405, needs engine.HandleMethodNotAllowed enabled
+func NoMethod(c *gin.Context) {
+	ErrorWithCode(c, http.StatusMethodNotAllowed, CodeMethodNotAllowed, "method not allowed", nil)
+}
+
+// Collects IDs set on the gin context by request ID / tracing middleware.
+// Returns nil when there is nothing to report
+func MetaFromContext(c *gin.Context) *Meta {
+	meta := Meta{
+		RequestID: c.GetString(string(logger.RequestIDKey)),
+		TraceID:   c.GetString(string(logger.TraceIDKey)),
+	}
+
+	if meta == (Meta{}) {
+		return nil
+	}
+	return &meta
+}
+
+// Error response carrying details in data (e.g. failed health checks)
+func ErrorWithData(c *gin.Context, status int, message string, data any) {
+	negotiate(c, status, Response{
+		Success:   false,
+		Message:   message,
+		Data:      data,
+		Meta:      MetaFromContext(c),
 		Timestamp: time.Now().Unix(),
 	})
 }
@@ -68,23 +157,40 @@ func GetPageFromQuery(c *gin.Context) int {
 	return page
 }
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
 func GetPageSizeFromQuery(c *gin.Context) int {
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
-	if pageSize < 1 {
-		pageSize = 20
+	return GetPageSizeFromQueryWithLimits(c, defaultPageSize, maxPageSize)
+}
+
+// Reads page_size, falling back to defaultSize when missing or invalid and clamping to maxSize
+func GetPageSizeFromQueryWithLimits(c *gin.Context, defaultSize, maxSize int) int {
+	pageSize, err := strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = defaultSize
 	}
-	if pageSize > 100 {
-		pageSize = 100
+	if pageSize > maxSize {
+		pageSize = maxSize
 	}
 	return pageSize
 }
 
+// A non-positive pageSize falls back to the default and a negative total
+// counts as 0, so a bad caller can't crash the request
 func NewPaginatedResponse(items any, page, pageSize, total int) PaginatedResponse {
-	totalPages := total / pageSize
-	if total%pageSize > 0 {
-		totalPages++
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if total < 0 {
+		total = 0
 	}
 
+	// Rounded up, at least 1 whenever there are items
+	totalPages := (total + pageSize - 1) / pageSize
+
 	return PaginatedResponse{
 		Items:      items,
 		Page:       page,
@@ -94,3 +200,50 @@ func NewPaginatedResponse(items any, page, pageSize, total int) PaginatedRespons
 		Timestamp:  time.Now().Unix(),
 	}
 }
+
+// 200 with a page of items, meta filled from the gin context like Success
+func Paginated(c *gin.Context, items any, page, pageSize, total int) {
+	resp := NewPaginatedResponse(items, page, pageSize, total)
+	resp.Meta = MetaFromContext(c)
+	negotiate(c, http.StatusOK, resp)
+}
+
+// Returns the ID after which the next page starts, uuidv7.Nil for the first page
+func GetCursorFromQuery(c *gin.Context) (uuidv7.UUID, error) {
+	cursor := c.Query("cursor")
+	if cursor == "" {
+		return uuidv7.Nil, nil
+	}
+	return DecodeCursor(cursor)
+}
+
+// Encodes last seen ID as an opaque cursor
+func EncodeCursor(lastID uuidv7.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(lastID[:])
+}
+
+func DecodeCursor(cursor string) (uuidv7.UUID, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(data) != len(uuidv7.Nil) {
+		return uuidv7.Nil, ErrInvalidCursor
+	}
+
+	var id uuidv7.UUID
+	copy(id[:], data)
+	return id, nil
+}
+
+// lastID is the key of the last item in items, ignored when there are no more pages
+func NewCursorPaginatedResponse(items any, lastID uuidv7.UUID, hasMore bool) CursorPaginatedResponse {
+	nextCursor := ""
+	if hasMore {
+		nextCursor = EncodeCursor(lastID)
+	}
+
+	return CursorPaginatedResponse{
+		Items:      items,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+		Timestamp:  time.Now().Unix(),
+	}
+}
@@ -0,0 +1,255 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"nexus/pkg/logger"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// Runs handler for a GET / and returns the recorded response
+func serve(handler gin.HandlerFunc, setup ...func(*http.Request)) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.GET("/", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, fn := range setup {
+		fn(req)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder) Response {
+	t.Helper()
+
+	var body Response
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body %q: %v", w.Body.String(), err)
+	}
+	return body
+}
+
+func withRequestID(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(string(logger.RequestIDKey), "req-1")
+		handler(c)
+	}
+}
+
+func TestMetaCarriesRequestID(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+	}{
+		{"success", func(c *gin.Context) { Success(c, http.StatusOK, "ok") }},
+		{"error", func(c *gin.Context) { Error(c, http.StatusBadRequest, "bad request", nil) }},
+		{"paginated", func(c *gin.Context) { Paginated(c, []string{"a"}, 1, 20, 1) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(withRequestID(tt.handler))
+
+			var body struct {
+				Meta *Meta `json:"meta"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body %q: %v", w.Body.String(), err)
+			}
+			if body.Meta == nil || body.Meta.RequestID != "req-1" {
+				t.Errorf("meta = %+v, want request_id req-1", body.Meta)
+			}
+		})
+	}
+}
+
+func TestMetaOmittedWithoutRequestID(t *testing.T) {
+	w := serve(func(c *gin.Context) { Success(c, http.StatusOK, "ok") })
+
+	if body := decodeResponse(t, w); body.Meta != nil {
+		t.Errorf("meta = %+v, want omitted", body.Meta)
+	}
+}
+
+func TestGetPageSizeFromQueryWithLimits(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 10},
+		{"page_size=25", 25},
+		{"page_size=500", 50},
+		{"page_size=0", 10},
+		{"page_size=-3", 10},
+		{"page_size=abc", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got int
+			serve(func(c *gin.Context) {
+				got = GetPageSizeFromQueryWithLimits(c, 10, 50)
+			}, func(r *http.Request) { r.URL.RawQuery = tt.query })
+
+			if got != tt.want {
+				t.Errorf("page size = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPageSizeFromQueryDefaults(t *testing.T) {
+	for query, want := range map[string]int{"": defaultPageSize, "page_size=1000": maxPageSize} {
+		var got int
+		serve(func(c *gin.Context) {
+			got = GetPageSizeFromQuery(c)
+		}, func(r *http.Request) { r.URL.RawQuery = query })
+
+		if got != want {
+			t.Errorf("%q: page size = %d, want %d", query, got, want)
+		}
+	}
+}
+
+func TestCreated(t *testing.T) {
+	w := serve(func(c *gin.Context) { Created(c, map[string]string{"id": "u1"}) })
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	body := decodeResponse(t, w)
+	if !body.Success || body.Timestamp == 0 {
+		t.Errorf("body = %+v, want a success envelope", body)
+	}
+	if data, ok := body.Data.(map[string]any); !ok || data["id"] != "u1" {
+		t.Errorf("data = %v, want the created resource", body.Data)
+	}
+}
+
+func TestAccepted(t *testing.T) {
+	w := serve(func(c *gin.Context) { Accepted(c, "queued") })
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+	if body := decodeResponse(t, w); !body.Success || body.Data != "queued" {
+		t.Errorf("body = %+v, want a success envelope", body)
+	}
+}
+
+func TestNoContent(t *testing.T) {
+	w := serve(NoContent)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty", w.Body.String())
+	}
+}
+
+func TestNoRouteAndNoMethodEnvelopes(t *testing.T) {
+	r := gin.New()
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(NoRoute)
+	r.NoMethod(NoMethod)
+	r.GET("/users", func(c *gin.Context) { Success(c, http.StatusOK, nil) })
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		code   string
+	}{
+		{"unknown path", http.MethodGet, "/missing", http.StatusNotFound, CodeNotFound},
+		{"unsupported method", http.MethodDelete, "/users", http.StatusMethodNotAllowed, CodeMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+				t.Errorf("Content-Type = %q, want JSON", got)
+			}
+			body := decodeResponse(t, w)
+			if body.Success || body.Code != tt.code || body.Message == "" || body.Timestamp == 0 {
+				t.Errorf("body = %+v, want an error envelope with code %s", body, tt.code)
+			}
+		})
+	}
+}
+
+func TestNewPaginatedResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		pageSize     int
+		total        int
+		wantPageSize int
+		wantTotal    int
+		wantPages    int
+	}{
+		{"zero page size", 0, 45, defaultPageSize, 45, 3},
+		{"negative page size", -5, 45, defaultPageSize, 45, 3},
+		{"zero total", 10, 0, 10, 0, 0},
+		{"negative total", 10, -3, 10, 0, 0},
+		{"fewer items than a page", 10, 1, 10, 1, 1},
+		{"exact pages", 10, 30, 10, 30, 3},
+		{"partial last page", 10, 31, 10, 31, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := NewPaginatedResponse([]string{}, 1, tt.pageSize, tt.total)
+
+			if resp.PageSize != tt.wantPageSize || resp.Total != tt.wantTotal || resp.TotalPages != tt.wantPages {
+				t.Errorf("page_size/total/total_pages = %d/%d/%d, want %d/%d/%d",
+					resp.PageSize, resp.Total, resp.TotalPages, tt.wantPageSize, tt.wantTotal, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestSuccessWithMessage(t *testing.T) {
+	w := serve(func(c *gin.Context) {
+		SuccessWithMessage(c, http.StatusOK, "password updated", map[string]bool{"changed": true})
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	body := decodeResponse(t, w)
+	if !body.Success || body.Message != "password updated" {
+		t.Errorf("success/message = %v/%q, want true/%q", body.Success, body.Message, "password updated")
+	}
+	if data, ok := body.Data.(map[string]any); !ok || data["changed"] != true {
+		t.Errorf("data = %v, want the payload", body.Data)
+	}
+}
+
+func TestSuccessOmitsMessage(t *testing.T) {
+	w := serve(func(c *gin.Context) { Success(c, http.StatusOK, "ok") })
+
+	var raw map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if _, ok := raw["message"]; ok {
+		t.Errorf("body = %v, want no message key", raw)
+	}
+}
@@ -0,0 +1,75 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// Describes a single field that failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
+}
+
+// Responds 422 with per-field validation errors
+func ValidationError(c *gin.Context, errs []FieldError) {
+	negotiate(c, http.StatusUnprocessableEntity, Response{
+		Success:   false,
+		Message:   "validation failed",
+		Code:      CodeValidationFailed,
+		Errors:    errs,
+		Meta:      MetaFromContext(c),
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// Converts binding error into field errors. Returns nil if err is not
+// a validator.ValidationErrors (e.g. malformed JSON)
+func FieldErrorsFromValidator(err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	result := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		result = append(result, FieldError{
+			Field:   fe.Field(),
+			Tag:     fe.Tag(),
+			Message: fieldErrorMessage(fe),
+		})
+	}
+
+	return result
+}
+
+func fieldErrorMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "uuid", "uuid4", "uuid7":
+		return "must be a valid UUID"
+	case "min":
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "len":
+		return fmt.Sprintf("must have length %s", fe.Param())
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "lte":
+		return fmt.Sprintf("must be less than or equal to %s", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", fe.Param())
+	default:
+		return fmt.Sprintf("failed on '%s' validation", fe.Tag())
+	}
+}
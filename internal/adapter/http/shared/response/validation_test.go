@@ -0,0 +1,68 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type signupRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8"`
+	Role     string `json:"role" binding:"omitempty,oneof=user admin"`
+}
+
+func TestValidationErrorMapsTags(t *testing.T) {
+	r := gin.New()
+	r.POST("/signup", func(c *gin.Context) {
+		var req signupRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ValidationError(c, FieldErrorsFromValidator(err))
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(`{"email":"not-an-email","password":"short","role":"root"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", w.Code)
+	}
+	body := decodeResponse(t, w)
+	if body.Code != CodeValidationFailed {
+		t.Errorf("code = %q, want %q", body.Code, CodeValidationFailed)
+	}
+
+	want := []FieldError{
+		{Field: "Email", Tag: "email", Message: "must be a valid email address"},
+		{Field: "Password", Tag: "min", Message: "must be at least 8"},
+		{Field: "Role", Tag: "oneof", Message: "must be one of: user admin"},
+	}
+	if !slices.Equal(body.Errors, want) {
+		t.Errorf("errors = %+v, want %+v", body.Errors, want)
+	}
+}
+
+func TestFieldErrorsFromValidatorRequired(t *testing.T) {
+	err := binding.Validator.ValidateStruct(&signupRequest{})
+
+	got := FieldErrorsFromValidator(err)
+	if len(got) != 2 || got[0].Tag != "required" || got[0].Message != "is required" {
+		t.Errorf("field errors = %+v, want required email and password", got)
+	}
+}
+
+func TestFieldErrorsFromValidatorIgnoresOtherErrors(t *testing.T) {
+	if got := FieldErrorsFromValidator(errors.New("unexpected EOF")); got != nil {
+		t.Errorf("field errors = %+v, want nil", got)
+	}
+}
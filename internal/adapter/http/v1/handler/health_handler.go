@@ -1,20 +1,42 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 	"nexus/internal/adapter/http/shared/response"
 	"nexus/pkg/version"
+	"runtime"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-type HealthHandler struct{}
+const checkTimeout = 2 * time.Second
 
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+// Dependency probed by the readiness endpoint (database, cache, external API...)
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
 }
 
+type checkResult struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type HealthHandler struct {
+	checkers []Checker
+}
+
+func NewHealthHandler(checkers ...Checker) *HealthHandler {
+	return &HealthHandler{
+		checkers: checkers,
+	}
+}
+
+// Liveness, reports only that the process is up
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
 	response.Success(c, http.StatusOK, gin.H{
 		"status":  "ok",
@@ -22,3 +44,75 @@ func (h *HealthHandler) HealthCheck(c *gin.Context) {
 		"time":    time.Now().Unix(),
 	})
 }
+
+// Build info of the running binary
+func (h *HealthHandler) Version(c *gin.Context) {
+	response.Success(c, http.StatusOK, gin.H{
+		"service":    version.ServiceID,
+		"version":    version.ServiceVersion,
+		"git_commit": version.GitCommit,
+		"build_time": version.BuildTime,
+		"go_version": runtime.Version(),
+	})
+}
+
+// Readiness, runs all checkers concurrently and fails if any of them fails
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkTimeout)
+	defer cancel()
+
+	results := h.runChecks(ctx)
+
+	ready := true
+	for _, result := range results {
+		if result.Status != "ok" {
+			ready = false
+			break
+		}
+	}
+
+	if !ready {
+		response.ErrorWithData(c, http.StatusServiceUnavailable, "service not ready", gin.H{
+			"status": "unavailable",
+			"checks": results,
+		})
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"status": "ok",
+		"checks": results,
+	})
+}
+
+func (h *HealthHandler) runChecks(ctx context.Context) map[string]checkResult {
+	results := make(map[string]checkResult, len(h.checkers))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, checker := range h.checkers {
+		wg.Add(1)
+		go func(checker Checker) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := checker.Check(ctx)
+
+			result := checkResult{
+				Status:    "ok",
+				LatencyMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				result.Status = "unavailable"
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results[checker.Name()] = result
+			mu.Unlock()
+		}(checker)
+	}
+	wg.Wait()
+
+	return results
+}
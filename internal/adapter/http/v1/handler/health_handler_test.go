@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"nexus/pkg/version"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// Checker with a fixed result
+type stubChecker struct {
+	name string
+	err  error
+}
+
+func (s stubChecker) Name() string {
+	return s.name
+}
+
+func (s stubChecker) Check(context.Context) error {
+	return s.err
+}
+
+type readinessBody struct {
+	Success bool `json:"success"`
+	Data    struct {
+		Status string                 `json:"status"`
+		Checks map[string]checkResult `json:"checks"`
+	} `json:"data"`
+}
+
+func getReadiness(t *testing.T, checkers ...Checker) (int, readinessBody) {
+	t.Helper()
+
+	r := gin.New()
+	r.GET("/ready", NewHealthHandler(checkers...).Readiness)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+	var body readinessBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body %q: %v", w.Body.String(), err)
+	}
+	return w.Code, body
+}
+
+func TestReadinessDatabase(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		state  string
+	}{
+		{"healthy", nil, http.StatusOK, "ok"},
+		{"unreachable", errors.New("connection refused"), http.StatusServiceUnavailable, "unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := getReadiness(t, stubChecker{name: "database", err: tt.err})
+
+			if status != tt.status {
+				t.Errorf("status = %d, want %d", status, tt.status)
+			}
+			if body.Data.Status != tt.state || body.Data.Checks["database"].Status != tt.state {
+				t.Errorf("data = %+v, want status %q", body.Data, tt.state)
+			}
+		})
+	}
+}
+
+// Sleeps before reporting healthy
+type slowChecker struct {
+	delay time.Duration
+}
+
+func (s slowChecker) Name() string {
+	return "search"
+}
+
+func (s slowChecker) Check(ctx context.Context) error {
+	select {
+	case <-time.After(s.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestReadinessMixedCheckers(t *testing.T) {
+	status, body := getReadiness(t,
+		stubChecker{name: "database"},
+		stubChecker{name: "cache", err: errors.New("redis: connection refused")},
+		slowChecker{delay: 20 * time.Millisecond},
+	)
+
+	if status != http.StatusServiceUnavailable || body.Success {
+		t.Errorf("status = %d, success = %v, want 503 and false", status, body.Success)
+	}
+
+	checks := body.Data.Checks
+	if len(checks) != 3 {
+		t.Fatalf("checks = %+v, want 3", checks)
+	}
+	if checks["database"].Status != "ok" || checks["search"].Status != "ok" {
+		t.Errorf("passing checks = %+v / %+v, want ok", checks["database"], checks["search"])
+	}
+	if cache := checks["cache"]; cache.Status != "unavailable" || cache.Error != "redis: connection refused" {
+		t.Errorf("cache = %+v, want unavailable with its error", cache)
+	}
+	if latency := checks["search"].LatencyMs; latency < 20 {
+		t.Errorf("search latency = %dms, want at least 20ms", latency)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	r := gin.New()
+	r.GET("/version", NewHealthHandler().Version)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	var body struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body %q: %v", w.Body.String(), err)
+	}
+
+	want := map[string]string{
+		"service":    version.ServiceID,
+		"version":    version.ServiceVersion,
+		"git_commit": version.GitCommit,
+		"build_time": version.BuildTime,
+		"go_version": runtime.Version(),
+	}
+	for field, value := range want {
+		if got, ok := body.Data[field]; !ok || got != value {
+			t.Errorf("%s = %q, want %q", field, got, value)
+		}
+	}
+}
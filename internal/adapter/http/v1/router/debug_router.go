@@ -0,0 +1,31 @@
+package router
+
+import (
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Profiling endpoints from net/http/pprof. Only mounted when server.pprof is
+// enabled, and always behind the handlers passed to Setup
+type DebugRouter struct{}
+
+func NewDebugRouter() *DebugRouter {
+	return &DebugRouter{}
+}
+
+// Expects the group to be mounted at /debug/pprof, pprof.Index builds its links from that path
+func (r *DebugRouter) Setup(rg *gin.RouterGroup, guards ...gin.HandlerFunc) {
+	rg.Use(guards...)
+
+	rg.GET("/", gin.WrapF(pprof.Index))
+	rg.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	rg.GET("/profile", gin.WrapF(pprof.Profile))
+	rg.GET("/symbol", gin.WrapF(pprof.Symbol))
+	rg.POST("/symbol", gin.WrapF(pprof.Symbol))
+	rg.GET("/trace", gin.WrapF(pprof.Trace))
+	// heap, goroutine, allocs, block, mutex, threadcreate
+	rg.GET("/:profile", func(c *gin.Context) {
+		pprof.Handler(c.Param("profile")).ServeHTTP(c.Writer, c.Request)
+	})
+}
@@ -18,4 +18,6 @@ func NewHealthRouter(handler *handler.HealthHandler) *HealthRouter {
 
 func (r *HealthRouter) Setup(rg *gin.RouterGroup) {
 	rg.GET("/health", r.handler.HealthCheck)
+	rg.GET("/ready", r.handler.Readiness)
+	rg.GET("/version", r.handler.Version)
 }
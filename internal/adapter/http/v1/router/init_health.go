@@ -2,7 +2,7 @@ package router
 
 import "nexus/internal/adapter/http/v1/handler"
 
-func InitHealthModule() *HealthRouter {
-	handler := handler.NewHealthHandler()
+func InitHealthModule(checkers ...handler.Checker) *HealthRouter {
+	handler := handler.NewHealthHandler(checkers...)
 	return NewHealthRouter(handler)
 }
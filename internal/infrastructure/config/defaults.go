@@ -0,0 +1,98 @@
+package config
+
+import "time"
+
+const (
+	defaultServerPort        = 8080
+	defaultReadTimeout       = 15 * time.Second
+	defaultWriteTimeout      = 15 * time.Second
+	defaultShutdownTimeout   = 10 * time.Second
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultIdleTimeout       = 60 * time.Second
+	defaultMaxOpenConns      = 25
+	defaultSSLMode           = "disable"
+	defaultCompressMinSize   = 1024
+)
+
+var (
+	defaultCORSMethods   = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	defaultCORSHeaders   = []string{"Authorization", "Content-Type", "X-Request-ID"}
+	defaultCompressTypes = []string{"application/json", "application/problem+json", "application/msgpack", "text/plain", "text/html", "text/css", "application/javascript"}
+)
+
+// Fills zero-valued fields, explicitly set values are left untouched
+func applyDefaults(config *AppConfig) {
+	if config.Server.Port == 0 {
+		config.Server.Port = defaultServerPort
+	}
+	if config.Server.ReadTimeout == 0 {
+		config.Server.ReadTimeout = defaultReadTimeout
+	}
+	if config.Server.WriteTimeout == 0 {
+		config.Server.WriteTimeout = defaultWriteTimeout
+	}
+	if config.Server.ShutdownTimeout == 0 {
+		config.Server.ShutdownTimeout = defaultShutdownTimeout
+	}
+	if config.Server.ReadHeaderTimeout == 0 {
+		config.Server.ReadHeaderTimeout = defaultReadHeaderTimeout
+	}
+	if config.Server.IdleTimeout == 0 {
+		config.Server.IdleTimeout = defaultIdleTimeout
+	}
+
+	if config.Database.MaxOpenConns == 0 {
+		config.Database.MaxOpenConns = defaultMaxOpenConns
+	}
+	if config.Database.SSLMode == "" {
+		config.Database.SSLMode = defaultSSLMode
+	}
+
+	for i := range config.Database.Replicas {
+		inheritFromPrimary(&config.Database.Replicas[i], &config.Database)
+	}
+
+	if len(config.CORS.AllowedMethods) == 0 {
+		config.CORS.AllowedMethods = defaultCORSMethods
+	}
+	if len(config.CORS.AllowedHeaders) == 0 {
+		config.CORS.AllowedHeaders = defaultCORSHeaders
+	}
+
+	if config.Compression.MinSize == 0 {
+		config.Compression.MinSize = defaultCompressMinSize
+	}
+	if len(config.Compression.ContentTypes) == 0 {
+		config.Compression.ContentTypes = defaultCompressTypes
+	}
+}
+
+func inheritFromPrimary(replica, primary *DatabaseSection) {
+	if replica.Port == 0 {
+		replica.Port = primary.Port
+	}
+	if replica.User == "" {
+		replica.User = primary.User
+	}
+	if replica.Password == "" {
+		replica.Password = primary.Password
+	}
+	if replica.Database == "" {
+		replica.Database = primary.Database
+	}
+	if replica.SSLMode == "" {
+		replica.SSLMode = primary.SSLMode
+	}
+	if replica.MaxOpenConns == 0 {
+		replica.MaxOpenConns = primary.MaxOpenConns
+	}
+	if replica.MaxIdleConns == 0 {
+		replica.MaxIdleConns = primary.MaxIdleConns
+	}
+	if replica.ConnMaxLifetime == 0 {
+		replica.ConnMaxLifetime = primary.ConnMaxLifetime
+	}
+	if replica.ConnMaxIdleTime == 0 {
+		replica.ConnMaxIdleTime = primary.ConnMaxIdleTime
+	}
+}
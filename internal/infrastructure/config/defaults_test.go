@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefaultServerTimeouts(t *testing.T) {
+	cfg := &AppConfig{Server: ServerSection{IdleTimeout: 2 * time.Minute}}
+	applyDefaults(cfg)
+
+	if cfg.Server.ReadHeaderTimeout != defaultReadHeaderTimeout {
+		t.Errorf("ReadHeaderTimeout = %v, want %v", cfg.Server.ReadHeaderTimeout, defaultReadHeaderTimeout)
+	}
+	if cfg.Server.IdleTimeout != 2*time.Minute {
+		t.Errorf("IdleTimeout = %v, want the configured 2m", cfg.Server.IdleTimeout)
+	}
+}
+
+func TestLoadMinimalConfigAppliesDefaults(t *testing.T) {
+	cfg, err := LoadAppConfig(writeConfig(t, "app.yaml", minimalConfig))
+	if err != nil {
+		t.Fatalf("LoadAppConfig: %v", err)
+	}
+
+	if cfg.Server.Port != defaultServerPort {
+		t.Errorf("server.port = %d, want %d", cfg.Server.Port, defaultServerPort)
+	}
+	if cfg.Server.ReadTimeout != defaultReadTimeout || cfg.Server.WriteTimeout != defaultWriteTimeout {
+		t.Errorf("server timeouts = %v/%v, want defaults", cfg.Server.ReadTimeout, cfg.Server.WriteTimeout)
+	}
+	if cfg.Server.ShutdownTimeout != defaultShutdownTimeout {
+		t.Errorf("server.shutdown_timeout = %v, want %v", cfg.Server.ShutdownTimeout, defaultShutdownTimeout)
+	}
+	if cfg.Database.MaxOpenConns != defaultMaxOpenConns || cfg.Database.SSLMode != defaultSSLMode {
+		t.Errorf("database = %d conns, sslmode %q, want defaults", cfg.Database.MaxOpenConns, cfg.Database.SSLMode)
+	}
+	if len(cfg.CORS.AllowedMethods) == 0 || len(cfg.Compression.ContentTypes) == 0 {
+		t.Error("list defaults were not applied")
+	}
+}
+
+func TestReplicaInheritsFromPrimary(t *testing.T) {
+	cfg := &AppConfig{Database: DatabaseSection{
+		Port:     5432,
+		User:     "postgres",
+		Password: "secret",
+		Database: "nexus",
+		Replicas: []DatabaseSection{{Host: "replica", User: "reader"}},
+	}}
+	applyDefaults(cfg)
+
+	replica := cfg.Database.Replicas[0]
+	if replica.Port != 5432 || replica.Password != "secret" || replica.Database != "nexus" {
+		t.Errorf("replica = %+v, want port, password and database from the primary", replica)
+	}
+	if replica.User != "reader" || replica.Host != "replica" {
+		t.Errorf("replica user/host = %q/%q, want its own", replica.User, replica.Host)
+	}
+}
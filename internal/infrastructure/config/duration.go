@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// yaml.v2 decodes a bare number into time.Duration as nanoseconds, so
+// `read_timeout: 30` silently becomes 30ns. Rejects numbers other than 0
+// for duration fields, values must carry a unit ("30s", "2m")
+func checkDurations(data []byte) error {
+	var raw map[any]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var errs []string
+	checkDurationValue(reflect.TypeOf(AppConfig{}), raw, "", &errs)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("durations need a unit (e.g. 30s, 5m): %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func checkDurationValue(t reflect.Type, value any, path string, errs *[]string) {
+	switch {
+	case t == durationType:
+		if isNonZeroNumber(value) {
+			*errs = append(*errs, fmt.Sprintf("%s: %v", path, value))
+		}
+	case t.Kind() == reflect.Struct:
+		fields, ok := value.(map[any]any)
+		if !ok {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			if v, ok := fields[name]; ok {
+				checkDurationValue(field.Type, v, joinPath(path, name), errs)
+			}
+		}
+	case t.Kind() == reflect.Slice:
+		items, ok := value.([]any)
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			checkDurationValue(t.Elem(), item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// Numbers as decoded by yaml.v2, strings like "30s" are not numbers
+func isNonZeroNumber(value any) bool {
+	switch v := value.(type) {
+	case int:
+		return v != 0
+	case int64:
+		return v != 0
+	case uint64:
+		return v != 0
+	case float64:
+		return v != 0
+	}
+	return false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
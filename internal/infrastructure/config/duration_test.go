@@ -0,0 +1,62 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDurationsWithUnit(t *testing.T) {
+	path := writeConfig(t, "app.yaml", minimalConfig+`
+server:
+  read_timeout: 30s
+  write_timeout: 2m
+  shutdown_timeout: 0
+`)
+
+	cfg, err := LoadAppConfig(path)
+	if err != nil {
+		t.Fatalf("LoadAppConfig: %v", err)
+	}
+
+	if cfg.Server.ReadTimeout != 30*time.Second {
+		t.Errorf("read_timeout = %v, want 30s", cfg.Server.ReadTimeout)
+	}
+	if cfg.Server.WriteTimeout != 2*time.Minute {
+		t.Errorf("write_timeout = %v, want 2m", cfg.Server.WriteTimeout)
+	}
+}
+
+func TestDurationsWithoutUnitRejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		yaml  string
+		field string
+	}{
+		{"integer", "server:\n  read_timeout: 30\n", "server.read_timeout: 30"},
+		{"float", "server:\n  idle_timeout: 1.5\n", "server.idle_timeout: 1.5"},
+		{"negative", "cors:\n  max_age: -5\n", "cors.max_age: -5"},
+		{"large", "jwt:\n  leeway: 18446744073709551615\n", "jwt.leeway: 18446744073709551615"},
+		{"in a list", "database:\n  replicas:\n    - host: replica\n      connect_timeout: 10\n", "database.replicas[0].connect_timeout: 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDurations([]byte(tt.yaml))
+			if err == nil {
+				t.Fatal("bare number accepted")
+			}
+			if !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("error %q doesn't name %q", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestDurationsZeroAccepted(t *testing.T) {
+	for _, value := range []string{"0", "0.0", "0s", `"30s"`} {
+		if err := checkDurations([]byte("server:\n  read_timeout: " + value + "\n")); err != nil {
+			t.Errorf("read_timeout: %s rejected: %v", value, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const envPrefix = "NEXUS"
+
+// Builds the config from environment variables only, no file is read.
+// Variables are named NEXUS_<SECTION>_<FIELD> after the yaml keys, e.g.
+// NEXUS_DATABASE_HOST, NEXUS_JWT_ACCESS_TOKEN_DURATION=15m, NEXUS_CORS_ALLOWED_ORIGINS=a,b.
+// Durations use time.ParseDuration, lists are comma-separated.
+// Database replicas can't be configured this way
+func LoadFromEnv() (*AppConfig, error) {
+	var config AppConfig
+
+	var errs []error
+	loadEnvValue(reflect.ValueOf(&config).Elem(), envPrefix, &errs)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to load config from env:\n%w", errors.Join(errs...))
+	}
+
+	applyDefaults(&config)
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func loadEnvValue(v reflect.Value, name string, errs *[]error) {
+	if v.Kind() == reflect.Struct {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			tag := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+			if tag == "" || tag == "-" {
+				continue
+			}
+			loadEnvValue(v.Field(i), name+"_"+strings.ToUpper(tag), errs)
+		}
+		return
+	}
+
+	raw, ok := os.LookupEnv(name)
+	if !ok {
+		return
+	}
+
+	if err := setFromString(v, raw); err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %w", name, err))
+	}
+}
+
+func setFromString(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported list type %s", v.Type())
+		}
+		items := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
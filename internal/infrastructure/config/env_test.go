@@ -0,0 +1,70 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Sets the variables LoadFromEnv needs for a valid config
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+
+	for name, value := range map[string]string{
+		"NEXUS_DATABASE_HOST":              "db.internal",
+		"NEXUS_DATABASE_PORT":              "5433",
+		"NEXUS_DATABASE_USER":              "nexus",
+		"NEXUS_DATABASE_DATABASE":          "nexus",
+		"NEXUS_JWT_SECRET":                 "test-secret-that-is-at-least-32-characters",
+		"NEXUS_JWT_ACCESS_TOKEN_DURATION":  "15m",
+		"NEXUS_JWT_REFRESH_TOKEN_DURATION": "24h",
+	} {
+		t.Setenv(name, value)
+	}
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("NEXUS_APP_DEBUG", "true")
+	t.Setenv("NEXUS_SERVER_READ_TIMEOUT", "45s")
+	t.Setenv("NEXUS_CORS_ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com,")
+
+	cfg, err := LoadFromEnv()
+	if err != nil {
+		t.Fatalf("LoadFromEnv: %v", err)
+	}
+
+	if cfg.Database.Host != "db.internal" || cfg.Database.Port != 5433 || cfg.Database.User != "nexus" {
+		t.Errorf("database = %+v, want values from env", cfg.Database)
+	}
+	if cfg.JWT.AccessTokenDuration != 15*time.Minute || cfg.JWT.RefreshTokenDuration != 24*time.Hour {
+		t.Errorf("jwt durations = %v/%v, want 15m/24h", cfg.JWT.AccessTokenDuration, cfg.JWT.RefreshTokenDuration)
+	}
+	if !cfg.App.Debug || cfg.Server.ReadTimeout != 45*time.Second {
+		t.Errorf("debug = %v, read_timeout = %v, want true and 45s", cfg.App.Debug, cfg.Server.ReadTimeout)
+	}
+	if want := []string{"https://a.example.com", "https://b.example.com"}; !slices.Equal(cfg.CORS.AllowedOrigins, want) {
+		t.Errorf("allowed_origins = %q, want %q", cfg.CORS.AllowedOrigins, want)
+	}
+	// Unset fields get defaults
+	if cfg.Server.Port != defaultServerPort {
+		t.Errorf("server.port = %d, want default %d", cfg.Server.Port, defaultServerPort)
+	}
+}
+
+func TestLoadFromEnvInvalidValues(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("NEXUS_DATABASE_PORT", "five")
+	t.Setenv("NEXUS_SERVER_READ_TIMEOUT", "30")
+
+	_, err := LoadFromEnv()
+	if err == nil {
+		t.Fatal("LoadFromEnv accepted invalid values")
+	}
+	for _, name := range []string{"NEXUS_DATABASE_PORT", "NEXUS_SERVER_READ_TIMEOUT"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not name %s", err, name)
+		}
+	}
+}
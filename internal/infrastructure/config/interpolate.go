@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+)
+
+// Matches ${VAR} and ${VAR:-default}
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// Replaces ${VAR} references in every string field with environment values
+func interpolateEnv(config *AppConfig) error {
+	var errs []error
+	interpolateValue(reflect.ValueOf(config).Elem(), &errs)
+	return errors.Join(errs...)
+}
+
+func interpolateValue(v reflect.Value, errs *[]error) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(expandEnv(v.String(), errs))
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				interpolateValue(v.Field(i), errs)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			interpolateValue(v.Index(i), errs)
+		}
+	}
+}
+
+func expandEnv(s string, errs *[]error) string {
+	return envVarPattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := envVarPattern.FindStringSubmatch(match)
+		name, hasDefault, def := groups[1], groups[2] != "", groups[3]
+
+		value, ok := os.LookupEnv(name)
+		if hasDefault && (!ok || value == "") {
+			return def
+		}
+		if !ok {
+			*errs = append(*errs, fmt.Errorf("environment variable %s is not set", name))
+			return match
+		}
+		return value
+	})
+}
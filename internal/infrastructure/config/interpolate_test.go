@@ -0,0 +1,67 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterpolateEnv(t *testing.T) {
+	t.Setenv("NEXUS_TEST_DB_HOST", "db.internal")
+	t.Setenv("NEXUS_TEST_EMPTY", "")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{"present", "${NEXUS_TEST_DB_HOST}", "db.internal", ""},
+		{"present with default", "${NEXUS_TEST_DB_HOST:-localhost}", "db.internal", ""},
+		{"missing with default", "${NEXUS_TEST_UNSET:-localhost}", "localhost", ""},
+		{"empty with default", "${NEXUS_TEST_EMPTY:-localhost}", "localhost", ""},
+		{"embedded", "postgres://${NEXUS_TEST_DB_HOST}:5432", "postgres://db.internal:5432", ""},
+		{"missing without default", "${NEXUS_TEST_UNSET}", "", "NEXUS_TEST_UNSET is not set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &AppConfig{Database: DatabaseSection{Host: tt.value}}
+			err := interpolateEnv(cfg)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("interpolateEnv: %v", err)
+			}
+			if cfg.Database.Host != tt.want {
+				t.Errorf("host = %q, want %q", cfg.Database.Host, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadInterpolatesNestedFields(t *testing.T) {
+	t.Setenv("NEXUS_TEST_DB_PASSWORD", "s3cret")
+	t.Setenv("NEXUS_TEST_ORIGIN", "https://app.example.com")
+
+	content := strings.Replace(minimalConfig, "user: postgres", "user: postgres\n  password: ${NEXUS_TEST_DB_PASSWORD}", 1)
+	path := writeConfig(t, "app.yaml", content+`
+cors:
+  allowed_origins: ["${NEXUS_TEST_ORIGIN}"]
+`)
+
+	cfg, err := LoadAppConfig(path)
+	if err != nil {
+		t.Fatalf("LoadAppConfig: %v", err)
+	}
+	if cfg.Database.Password != "s3cret" {
+		t.Errorf("password = %q, want interpolated", cfg.Database.Password)
+	}
+	if got := cfg.CORS.AllowedOrigins; len(got) != 1 || got[0] != "https://app.example.com" {
+		t.Errorf("allowed_origins = %q, want interpolated", got)
+	}
+}
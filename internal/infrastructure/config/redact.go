@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v2"
+)
+
+const redactedValue = "[REDACTED]"
+
+// Returns a copy of the config with fields tagged sensitive:"true" masked,
+// safe to log. The original config is left untouched.
+func (c *AppConfig) Redacted() *AppConfig {
+	redacted := *c
+	// Anchor definitions are duplicated in the sections that use them
+	redacted.Extensions = nil
+	redactValue(reflect.ValueOf(&redacted).Elem())
+	return &redacted
+}
+
+func (c *AppConfig) String() string {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Sprintf("<config: %v>", err)
+	}
+	return string(data)
+}
+
+func redactValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			if t.Field(i).Tag.Get("sensitive") == "true" && field.Kind() == reflect.String {
+				if field.String() != "" {
+					field.SetString(redactedValue)
+				}
+				continue
+			}
+			redactValue(field)
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		// Copy the backing array so masking doesn't leak into the original
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(copied, v)
+		for i := 0; i < copied.Len(); i++ {
+			redactValue(copied.Index(i))
+		}
+		v.Set(copied)
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedactedHidesSecrets(t *testing.T) {
+	cfg := &AppConfig{
+		Database: DatabaseSection{
+			Host:     "db.internal",
+			Password: "db-password",
+			Replicas: []DatabaseSection{{Host: "replica.internal", Password: "replica-password"}},
+		},
+		JWT: JWTSection{
+			Secret:        "jwt-secret",
+			RefreshSecret: "jwt-refresh-secret",
+		},
+	}
+
+	outputs := map[string]string{
+		"String": cfg.String(),
+		"%v":     fmt.Sprintf("%v", cfg),
+	}
+	for name, output := range outputs {
+		for _, secret := range []string{"db-password", "replica-password", "jwt-secret", "jwt-refresh-secret"} {
+			if strings.Contains(output, secret) {
+				t.Errorf("%s output contains %q:\n%s", name, secret, output)
+			}
+		}
+		if !strings.Contains(output, "db.internal") {
+			t.Errorf("%s output lost non-sensitive fields:\n%s", name, output)
+		}
+	}
+
+	if cfg.Database.Password != "db-password" || cfg.Database.Replicas[0].Password != "replica-password" {
+		t.Error("Redacted modified the original config")
+	}
+}
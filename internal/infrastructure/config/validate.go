@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const minJWTSecretLength = 32
+
+// Checks required fields and value ranges, reporting every problem at once
+func (c *AppConfig) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validPort(c.Server.Port), "server.port must be between 1 and 65535, got %d", c.Server.Port)
+
+	check(c.Database.Host != "", "database.host is required")
+	check(validPort(c.Database.Port), "database.port must be between 1 and 65535, got %d", c.Database.Port)
+	check(c.Database.User != "", "database.user is required")
+	check(c.Database.Database != "", "database.database is required")
+	check(c.Database.MaxOpenConns > 0, "database.max_open_conns must be positive, got %d", c.Database.MaxOpenConns)
+	check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns must not be negative, got %d", c.Database.MaxIdleConns)
+	check(c.Database.ConnectMaxAttempts >= 0, "database.connect_max_attempts must not be negative, got %d", c.Database.ConnectMaxAttempts)
+	for i, replica := range c.Database.Replicas {
+		check(replica.Host != "", "database.replicas[%d].host is required", i)
+		check(validPort(replica.Port), "database.replicas[%d].port must be between 1 and 65535, got %d", i, replica.Port)
+	}
+
+	check(len(c.JWT.Secret) >= minJWTSecretLength, "jwt.secret must be at least %d characters", minJWTSecretLength)
+	check(c.JWT.RefreshSecret == "" || len(c.JWT.RefreshSecret) >= minJWTSecretLength,
+		"jwt.refresh_secret must be at least %d characters", minJWTSecretLength)
+	check(c.JWT.RefreshSecret == "" || c.JWT.RefreshSecret != c.JWT.Secret,
+		"jwt.refresh_secret must differ from jwt.secret")
+	check(c.JWT.AccessTokenDuration > 0, "jwt.access_token_duration must be positive")
+	check(c.JWT.RefreshTokenDuration > 0, "jwt.refresh_token_duration must be positive")
+
+	check(!(c.CORS.AllowCredentials && slices.Contains(c.CORS.AllowedOrigins, "*")),
+		"cors.allowed_origins must list explicit origins when cors.allow_credentials is enabled")
+	check(c.CORS.MaxAge >= 0, "cors.max_age must not be negative")
+
+	check(c.Compression.MinSize >= 0, "compression.min_size must not be negative, got %d", c.Compression.MinSize)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func validPort(port int) bool {
+	return port >= 1 && port <= 65535
+}
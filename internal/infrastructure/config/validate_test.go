@@ -0,0 +1,93 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() *AppConfig {
+	cfg := &AppConfig{
+		Database: DatabaseSection{Host: "localhost", Port: 5432, User: "postgres", Database: "nexus"},
+		JWT: JWTSection{
+			Secret:               "test-secret-that-is-at-least-32-characters",
+			AccessTokenDuration:  15 * time.Minute,
+			RefreshTokenDuration: 24 * time.Hour,
+		},
+	}
+	applyDefaults(cfg)
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*AppConfig)
+		want   []string
+	}{
+		{"valid", func(*AppConfig) {}, nil},
+		{
+			name:   "bad ports",
+			modify: func(c *AppConfig) { c.Server.Port = 70000; c.Database.Port = 0 },
+			want:   []string{"server.port", "database.port"},
+		},
+		{
+			name:   "missing database fields",
+			modify: func(c *AppConfig) { c.Database.Host, c.Database.User, c.Database.Database = "", "", "" },
+			want:   []string{"database.host is required", "database.user is required", "database.database is required"},
+		},
+		{
+			name:   "short jwt secret",
+			modify: func(c *AppConfig) { c.JWT.Secret = "short" },
+			want:   []string{"jwt.secret must be at least 32 characters"},
+		},
+		{
+			name:   "refresh secret equal to secret",
+			modify: func(c *AppConfig) { c.JWT.RefreshSecret = c.JWT.Secret },
+			want:   []string{"jwt.refresh_secret must differ"},
+		},
+		{
+			name:   "wildcard origin with credentials",
+			modify: func(c *AppConfig) { c.CORS.AllowedOrigins = []string{"*"}; c.CORS.AllowCredentials = true },
+			want:   []string{"cors.allowed_origins"},
+		},
+		{
+			name:   "replica without host",
+			modify: func(c *AppConfig) { c.Database.Replicas = []DatabaseSection{{Port: 5432}} },
+			want:   []string{"database.replicas[0].host is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate accepted an invalid config")
+			}
+			// Every problem is reported at once
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	path := writeConfig(t, "app.yaml", strings.Replace(minimalConfig, "host: localhost", "host: \"\"", 1))
+
+	_, err := LoadAppConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "database.host is required") {
+		t.Errorf("err = %v, want database.host is required", err)
+	}
+}
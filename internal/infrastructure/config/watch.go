@@ -0,0 +1,78 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"nexus/pkg/logger"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const watchPollInterval = 2 * time.Second
+
+// Re-reads config on SIGHUP or when the file modification time changes and
+// passes it to onReload. A config that fails to load or validate is ignored
+// and the previous one stays in effect. Blocks until ctx is done.
+//
+// Only fields that are read at runtime can be hot-reloaded, it's up to
+// onReload to apply them. Safe to reload:
+//   - database.max_open_conns, max_idle_conns, conn_max_lifetime, conn_max_idle_time
+//
+// Require a restart:
+//   - server.* (listener and timeouts are set on startup)
+//   - database connection parameters (host, port, user, password, database, sslmode)
+//   - jwt.* (JWT manager is created once)
+//   - app.*
+func Watch(ctx context.Context, path string, onReload func(*AppConfig)) {
+	if path == "" {
+		path = defaultConfigPath
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	lastModTime := fileModTime(path)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sighup:
+			logger.Info("Received SIGHUP, reloading config", slog.String("path", path))
+			lastModTime = fileModTime(path)
+			reload(path, onReload)
+		case <-ticker.C:
+			modTime := fileModTime(path)
+			if modTime.Equal(lastModTime) {
+				continue
+			}
+			lastModTime = modTime
+			logger.Info("Config file changed, reloading", slog.String("path", path))
+			reload(path, onReload)
+		}
+	}
+}
+
+func reload(path string, onReload func(*AppConfig)) {
+	config, err := LoadAppConfig(path)
+	if err != nil {
+		logger.Error("Config reload failed, keeping previous config", slog.Any("error", err))
+		return
+	}
+
+	onReload(config)
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
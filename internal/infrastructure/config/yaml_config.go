@@ -3,16 +3,24 @@ package config
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
 type AppConfig struct {
-	App      AppSection      `yaml:"app"`
-	Server   ServerSection   `yaml:"server"`
-	Database DatabaseSection `yaml:"database"`
-	JWT      JWTSection      `yaml:"jwt"`
+	App         AppSection         `yaml:"app"`
+	Server      ServerSection      `yaml:"server"`
+	Database    DatabaseSection    `yaml:"database"`
+	JWT         JWTSection         `yaml:"jwt"`
+	CORS        CORSSection        `yaml:"cors"`
+	Compression CompressionSection `yaml:"compression"`
+	// Top-level x-* keys, only meant to hold YAML anchors, e.g.
+	//   x-pool: &pool {max_open_conns: 25}
+	//   database: {<<: *pool, host: db}
+	Extensions map[string]any `yaml:",inline"`
 }
 
 type AppSection struct {
@@ -29,46 +37,130 @@ type ServerSection struct {
 	ReadTimeout     time.Duration `yaml:"read_timeout"`
 	WriteTimeout    time.Duration `yaml:"write_timeout"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// Slow clients (Slowloris) can't hold connections open with partial headers
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	// Mounts /debug/pprof for admins, off unless explicitly enabled
+	Pprof bool `yaml:"pprof"`
 }
 
 type DatabaseSection struct {
 	Host            string        `yaml:"host"`
 	Port            int           `yaml:"port"`
 	User            string        `yaml:"user"`
-	Password        string        `yaml:"password"`
+	Password        string        `yaml:"password" sensitive:"true"`
 	Database        string        `yaml:"database"`
 	SSLMode         string        `yaml:"sslmode"`
 	MaxOpenConns    int           `yaml:"max_open_conns"`
 	MaxIdleConns    int           `yaml:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
 	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	// Startup retries while the database is not ready yet
+	ConnectMaxAttempts int           `yaml:"connect_max_attempts"`
+	ConnectTimeout     time.Duration `yaml:"connect_timeout"`
+	// Read replicas, unset fields are inherited from the primary (except host)
+	Replicas []DatabaseSection `yaml:"replicas"`
 }
 
 type JWTSection struct {
-	Secret               string        `yaml:"secret"`
+	Secret               string        `yaml:"secret" sensitive:"true"`
+	RefreshSecret        string        `yaml:"refresh_secret" sensitive:"true"` // Optional, defaults to secret
 	AccessTokenDuration  time.Duration `yaml:"access_token_duration"`
 	RefreshTokenDuration time.Duration `yaml:"refresh_token_duration"`
 	Issuer               string        `yaml:"issuer"`
+	Audience             []string      `yaml:"audience"`
+	Leeway               time.Duration `yaml:"leeway"`
 }
 
+type CORSSection struct {
+	// Exact origins, "*" allows any origin (not allowed together with credentials)
+	AllowedOrigins   []string      `yaml:"allowed_origins"`
+	AllowedMethods   []string      `yaml:"allowed_methods"`
+	AllowedHeaders   []string      `yaml:"allowed_headers"`
+	ExposedHeaders   []string      `yaml:"exposed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+type CompressionSection struct {
+	Enabled bool `yaml:"enabled"`
+	// Smaller bodies are sent uncompressed, gzip overhead isn't worth it
+	MinSize int `yaml:"min_size"`
+	// Media types to compress, already compressed ones (images) should not be listed
+	ContentTypes []string `yaml:"content_types"`
+}
+
+const (
+	defaultConfigPath = "config/app.yaml"
+	extensionPrefix   = "x-"
+)
+
 func Load() (*AppConfig, error) {
 	return LoadAppConfig("")
 }
 
 func LoadAppConfig(configPath string) (*AppConfig, error) {
 	if configPath == "" {
-		configPath = "config/app.yaml"
+		configPath = defaultConfigPath
 	}
 
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+	return LoadLayered(configPath)
+}
+
+// Loads files in order, each one overriding only the fields it sets in the
+// previous ones (nested sections are merged, lists are replaced).
+// E.g. LoadLayered("config/app.yaml", "config/app.production.yaml")
+func LoadLayered(paths ...string) (*AppConfig, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no config files given")
 	}
 
 	var config AppConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		if err := checkDurations(data); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+
+		// Decoding into the same struct keeps fields absent from this layer.
+		// Strict mode rejects unknown keys, so typos fail loudly
+		if err := yaml.UnmarshalStrict(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+		if err := checkExtensions(config.Extensions); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	}
+
+	if err := interpolateEnv(&config); err != nil {
+		return nil, fmt.Errorf("failed to interpolate config: %w", err)
+	}
+
+	applyDefaults(&config)
+
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
 
 	return &config, nil
 }
+
+// Unknown top-level keys end up in Extensions, only x-* ones are allowed there
+func checkExtensions(extensions map[string]any) error {
+	var unknown []string
+	for key := range extensions {
+		if !strings.HasPrefix(key, extensionPrefix) {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown config keys: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Smallest config that passes validation
+const minimalConfig = `
+database:
+  host: localhost
+  port: 5432
+  user: postgres
+  database: nexus
+jwt:
+  secret: test-secret-that-is-at-least-32-characters
+  access_token_duration: 15m
+  refresh_token_duration: 24h
+`
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadSampleConfig(t *testing.T) {
+	cfg, err := LoadAppConfig(filepath.Join("..", "..", "..", "config", "app.yaml"))
+	if err != nil {
+		t.Fatalf("LoadAppConfig: %v", err)
+	}
+
+	if cfg.App.Name == "" || cfg.App.Version == "" {
+		t.Errorf("app = %+v, want name and version", cfg.App)
+	}
+	if cfg.JWT.Secret == "" {
+		t.Error("jwt secret is empty")
+	}
+	if cfg.JWT.AccessTokenDuration != 15*time.Minute || cfg.JWT.RefreshTokenDuration != 168*time.Hour {
+		t.Errorf("jwt durations = %v/%v, want 15m/168h", cfg.JWT.AccessTokenDuration, cfg.JWT.RefreshTokenDuration)
+	}
+}
+
+func TestLoadLayeredOverridesOnlySetFields(t *testing.T) {
+	base := writeConfig(t, "app.yaml", minimalConfig+`
+server:
+  port: 8080
+  read_timeout: 30s
+cors:
+  allowed_origins: ["http://localhost:3000", "http://localhost:5173"]
+`)
+	overlay := writeConfig(t, "app.production.yaml", `
+database:
+  host: db.prod.internal
+server:
+  port: 9090
+cors:
+  allowed_origins: ["https://app.example.com"]
+`)
+
+	cfg, err := LoadLayered(base, overlay)
+	if err != nil {
+		t.Fatalf("LoadLayered: %v", err)
+	}
+
+	// Set by the overlay
+	if cfg.Database.Host != "db.prod.internal" || cfg.Server.Port != 9090 {
+		t.Errorf("host/port = %q/%d, want the overlay's", cfg.Database.Host, cfg.Server.Port)
+	}
+	// Siblings in the same sections keep the base values
+	if cfg.Database.User != "postgres" || cfg.Database.Port != 5432 || cfg.Server.ReadTimeout != 30*time.Second {
+		t.Errorf("database user/port = %q/%d, read_timeout = %v, want the base values",
+			cfg.Database.User, cfg.Database.Port, cfg.Server.ReadTimeout)
+	}
+	if cfg.JWT.AccessTokenDuration != 15*time.Minute {
+		t.Errorf("jwt.access_token_duration = %v, want the base 15m", cfg.JWT.AccessTokenDuration)
+	}
+	// Lists are replaced, not appended
+	if got := cfg.CORS.AllowedOrigins; len(got) != 1 || got[0] != "https://app.example.com" {
+		t.Errorf("allowed_origins = %q, want only the overlay's", got)
+	}
+}
+
+func TestLoadLayeredMissingOverlay(t *testing.T) {
+	base := writeConfig(t, "app.yaml", minimalConfig)
+
+	if _, err := LoadLayered(base, filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("missing overlay was ignored")
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra string
+		want  string
+	}{
+		{"top-level typo", "databse:\n  host: localhost\n", "unknown config keys: databse"},
+		{"nested typo", "server:\n  prot: 8080\n", "field prot not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, "app.yaml", minimalConfig+tt.extra)
+
+			_, err := LoadAppConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadAppConfig err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAllowsExtensionsAndAnchors(t *testing.T) {
+	path := writeConfig(t, "app.yaml", `
+x-timeouts: &timeouts
+  read_timeout: 20s
+  write_timeout: 20s
+server:
+  <<: *timeouts
+  port: 8080
+`+minimalConfig)
+
+	cfg, err := LoadAppConfig(path)
+	if err != nil {
+		t.Fatalf("LoadAppConfig: %v", err)
+	}
+	if cfg.Server.ReadTimeout != 20*time.Second || cfg.Server.WriteTimeout != 20*time.Second {
+		t.Errorf("read/write timeout = %v/%v, want 20s from the anchor", cfg.Server.ReadTimeout, cfg.Server.WriteTimeout)
+	}
+	if cfg.Server.Port != 8080 {
+		t.Errorf("port = %d, want 8080", cfg.Server.Port)
+	}
+}
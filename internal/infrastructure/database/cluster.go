@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"nexus/internal/infrastructure/config"
+	"nexus/pkg/logger"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const replicaPingTimeout = 2 * time.Second
+
+// Primary connection plus optional read replicas.
+// Reads go to a replica only when explicitly requested via Replica()
+type Cluster struct {
+	primary  *sqlx.DB
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+type replica struct {
+	addr    string
+	db      *sqlx.DB
+	healthy atomic.Bool
+}
+
+// Connects to the primary (required) and replicas. An unreachable replica
+// doesn't fail startup, it's kept out of rotation until a health check passes
+func NewPostgresCluster(cfg *config.DatabaseSection) (*Cluster, error) {
+	primary, err := NewPostgresConnection(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := &Cluster{primary: primary}
+
+	for i := range cfg.Replicas {
+		replicaCfg := &cfg.Replicas[i]
+
+		db, err := sqlx.Open("postgres", postgresDSN(replicaCfg))
+		if err != nil {
+			_ = cluster.Close()
+			return nil, fmt.Errorf("failed to open replica %s: %w", replicaCfg.Host, err)
+		}
+		configurePool(db, replicaCfg)
+
+		r := &replica{
+			addr: fmt.Sprintf("%s:%d", replicaCfg.Host, replicaCfg.Port),
+			db:   db,
+		}
+		cluster.replicas = append(cluster.replicas, r)
+
+		if err := pingReplica(context.Background(), r); err != nil {
+			logger.Warn("Replica unavailable, excluded from rotation",
+				slog.String("replica", r.addr),
+				slog.Any("error", err))
+			continue
+		}
+
+		r.healthy.Store(true)
+		logger.Info("Replica connection established", slog.String("replica", r.addr))
+	}
+
+	return cluster, nil
+}
+
+func (c *Cluster) Primary() *sqlx.DB {
+	return c.primary
+}
+
+// Returns next healthy replica (round-robin), falls back to primary when none is healthy
+func (c *Cluster) Replica() *sqlx.DB {
+	count := uint64(len(c.replicas))
+	if count == 0 {
+		return c.primary
+	}
+
+	start := c.next.Add(1)
+	for i := uint64(0); i < count; i++ {
+		r := c.replicas[(start+i)%count]
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+
+	return c.primary
+}
+
+// Pings replicas every interval, removing failing ones from rotation and
+// returning recovered ones. Blocks until ctx is done
+func (c *Cluster) RunHealthChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.checkReplicas(ctx)
+		}
+	}
+}
+
+func (c *Cluster) checkReplicas(ctx context.Context) {
+	for _, r := range c.replicas {
+		err := pingReplica(ctx, r)
+		wasHealthy := r.healthy.Swap(err == nil)
+
+		switch {
+		case err != nil && wasHealthy:
+			logger.Warn("Replica health check failed, removed from rotation",
+				slog.String("replica", r.addr),
+				slog.Any("error", err))
+		case err == nil && !wasHealthy:
+			logger.Info("Replica recovered, returned to rotation", slog.String("replica", r.addr))
+		}
+	}
+}
+
+func pingReplica(ctx context.Context, r *replica) error {
+	ctx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+	defer cancel()
+
+	return r.db.PingContext(ctx)
+}
+
+func (c *Cluster) Close() error {
+	errs := []error{c.primary.Close()}
+	for _, r := range c.replicas {
+		errs = append(errs, r.db.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"nexus/internal/infrastructure/config"
+	"nexus/pkg/logger"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// *sqlx.DB together with the pool limits it was configured with
+type DB struct {
+	*sqlx.DB
+	maxOpenConns atomic.Int64
+	maxIdleConns atomic.Int64
+}
+
+// Connection pool statistics with configured limits for context
+type PoolStats struct {
+	MaxOpenConns      int           `json:"max_open_conns"`
+	MaxIdleConns      int           `json:"max_idle_conns"`
+	OpenConnections   int           `json:"open_connections"`
+	InUse             int           `json:"in_use"`
+	Idle              int           `json:"idle"`
+	WaitCount         int64         `json:"wait_count"`
+	WaitDuration      time.Duration `json:"wait_duration"`
+	MaxIdleClosed     int64         `json:"max_idle_closed"`
+	MaxLifetimeClosed int64         `json:"max_lifetime_closed"`
+}
+
+func NewDB(db *sqlx.DB, cfg *config.DatabaseSection) *DB {
+	d := &DB{DB: db}
+	d.maxOpenConns.Store(int64(cfg.MaxOpenConns))
+	d.maxIdleConns.Store(int64(cfg.MaxIdleConns))
+	return d
+}
+
+// Applies pool settings at runtime (e.g. on config reload)
+func (d *DB) ApplyPoolConfig(cfg *config.DatabaseSection) {
+	configurePool(d.DB, cfg)
+	d.maxOpenConns.Store(int64(cfg.MaxOpenConns))
+	d.maxIdleConns.Store(int64(cfg.MaxIdleConns))
+}
+
+func (d *DB) Stats() PoolStats {
+	stats := d.DB.Stats()
+
+	return PoolStats{
+		MaxOpenConns:      int(d.maxOpenConns.Load()),
+		MaxIdleConns:      int(d.maxIdleConns.Load()),
+		OpenConnections:   stats.OpenConnections,
+		InUse:             stats.InUse,
+		Idle:              stats.Idle,
+		WaitCount:         stats.WaitCount,
+		WaitDuration:      stats.WaitDuration,
+		MaxIdleClosed:     stats.MaxIdleClosed,
+		MaxLifetimeClosed: stats.MaxLifetimeClosed,
+	}
+}
+
+// Logs pool stats every interval, warning when the pool is saturated.
+// Blocks until ctx is done
+func (d *DB) LogStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := d.Stats()
+			attrs := []any{
+				slog.Int("max_open_conns", stats.MaxOpenConns),
+				slog.Int("open", stats.OpenConnections),
+				slog.Int("in_use", stats.InUse),
+				slog.Int("idle", stats.Idle),
+				slog.Int64("wait_count", stats.WaitCount),
+				slog.Duration("wait_duration", stats.WaitDuration),
+			}
+
+			if stats.MaxOpenConns > 0 && stats.InUse >= stats.MaxOpenConns {
+				logger.Warn("Database connection pool saturated", attrs...)
+			} else {
+				logger.Debug("Database connection pool stats", attrs...)
+			}
+		}
+	}
+}
+
+// Name identifies the database in health check results
+func (d *DB) Name() string {
+	return "database"
+}
+
+// Check pings the database, used by the readiness endpoint
+func (d *DB) Check(ctx context.Context) error {
+	return d.HealthCheck(ctx)
+}
+
+// Pings the database within the caller's deadline. A done context fails
+// right away instead of waiting for a pooled connection
+func (d *DB) HealthCheck(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("database health check: %w", err)
+	}
+
+	if err := d.PingContext(ctx); err != nil {
+		return fmt.Errorf("database health check: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"nexus/internal/infrastructure/config"
+	"testing"
+	"time"
+)
+
+func TestDBCheck(t *testing.T) {
+	errUnreachable := errors.New("dial tcp 10.0.0.5:5432: connect: connection refused")
+
+	tests := []struct {
+		name    string
+		pingErr error
+	}{
+		{"healthy", nil},
+		{"unreachable", errUnreachable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, sqlxDB := newFakeDB(t)
+			fake.pingErr = tt.pingErr
+			db := NewDB(sqlxDB, &config.DatabaseSection{})
+
+			err := db.Check(context.Background())
+			if !errors.Is(err, tt.pingErr) {
+				t.Errorf("Check err = %v, want %v", err, tt.pingErr)
+			}
+		})
+	}
+}
+
+func TestHealthCheckCancelledContext(t *testing.T) {
+	_, sqlxDB := newFakeDB(t)
+	db := NewDB(sqlxDB, &config.DatabaseSection{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := db.HealthCheck(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("HealthCheck err = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("HealthCheck took %v, want a prompt failure", elapsed)
+	}
+}
+
+func TestHealthCheckHonoursDeadlineWithExhaustedPool(t *testing.T) {
+	_, sqlxDB := newFakeDB(t)
+	sqlxDB.SetMaxOpenConns(1)
+	db := NewDB(sqlxDB, &config.DatabaseSection{MaxOpenConns: 1})
+
+	// Hold the only connection so the ping has to wait for one
+	conn, err := sqlxDB.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("Conn: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = db.HealthCheck(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("HealthCheck err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("HealthCheck took %v, want it bounded by the deadline", elapsed)
+	}
+}
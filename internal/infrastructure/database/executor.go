@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Query methods shared by *sqlx.DB and *sqlx.Tx, so repository methods
+// can be written once and work both inside and outside a transaction
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error)
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+// Returns the transaction from ctx if present, otherwise the pool
+func FromContext(ctx context.Context, db *sqlx.DB) Executor {
+	if tx, ok := GetTx(ctx); ok {
+		return tx
+	}
+	return db
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Repository written once against Executor
+type userRepo struct {
+	db *sqlx.DB
+}
+
+func (r *userRepo) Deactivate(ctx context.Context) error {
+	_, err := FromContext(ctx, r.db).ExecContext(ctx, "UPDATE users SET active = false")
+	return err
+}
+
+func TestRepositoryMethodOutsideTransaction(t *testing.T) {
+	fake, db := newFakeDB(t)
+	repo := &userRepo{db: db}
+
+	if err := repo.Deactivate(context.Background()); err != nil {
+		t.Fatalf("Deactivate: %v", err)
+	}
+
+	want := []string{"UPDATE users SET active = false"}
+	if got := fake.Statements(); !slices.Equal(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+}
+
+func TestRepositoryMethodJoinsOuterTransaction(t *testing.T) {
+	fake, db := newFakeDB(t)
+	repo := &userRepo{db: db}
+	tm := NewTransactionManager(db)
+
+	err := tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+		if err := repo.Deactivate(ctx); err != nil {
+			return err
+		}
+		return errInner
+	})
+	if !errors.Is(err, errInner) {
+		t.Fatalf("err = %v, want %v", err, errInner)
+	}
+
+	// The update was part of the transaction and rolled back with it
+	want := []string{"BEGIN", "UPDATE users SET active = false", "ROLLBACK"}
+	if got := fake.Statements(); !slices.Equal(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	_, db := newFakeDB(t)
+	tm := NewTransactionManager(db)
+
+	if _, ok := FromContext(context.Background(), db).(*sqlx.DB); !ok {
+		t.Error("FromContext without a transaction is not the pool")
+	}
+
+	err := tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+		if _, ok := FromContext(ctx, db).(*sqlx.Tx); !ok {
+			t.Error("FromContext inside a transaction is not the transaction")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction: %v", err)
+	}
+}
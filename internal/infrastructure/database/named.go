@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Implemented by both *sqlx.DB and *sqlx.Tx
+type namedExecutor interface {
+	sqlx.ExtContext
+	BindNamed(query string, arg any) (string, []any, error)
+}
+
+func namedFromContext(ctx context.Context, db *sqlx.DB) namedExecutor {
+	if tx, ok := GetTx(ctx); ok {
+		return tx
+	}
+	return db
+}
+
+// Runs a named query (:name params from a struct or map), inside the ctx transaction if any
+func NamedExecContext(ctx context.Context, db *sqlx.DB, query string, arg any) (sql.Result, error) {
+	return sqlx.NamedExecContext(ctx, namedFromContext(ctx, db), query, arg)
+}
+
+// Scans a single row of a named query into dest, inside the ctx transaction if any
+func NamedGetContext(ctx context.Context, db *sqlx.DB, dest any, query string, arg any) error {
+	ext := namedFromContext(ctx, db)
+
+	bound, args, err := ext.BindNamed(query, arg)
+	if err != nil {
+		return err
+	}
+
+	return explainScanError(ctx, sqlx.GetContext(ctx, ext, dest, bound, args...), dest)
+}
+
+// Scans all rows of a named query into dest, inside the ctx transaction if any
+func NamedSelectContext(ctx context.Context, db *sqlx.DB, dest any, query string, arg any) error {
+	ext := namedFromContext(ctx, db)
+
+	bound, args, err := ext.BindNamed(query, arg)
+	if err != nil {
+		return err
+	}
+
+	return explainScanError(ctx, sqlx.SelectContext(ctx, ext, dest, bound, args...), dest)
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"testing"
+)
+
+type namedUser struct {
+	ID    int    `db:"id"`
+	Email string `db:"email"`
+}
+
+func TestNamedExecJoinsOuterTransaction(t *testing.T) {
+	fake, db := newFakeDB(t)
+
+	var args []driver.NamedValue
+	fake.exec = func(_ string, a []driver.NamedValue) (driver.Result, error) {
+		args = a
+		return driver.RowsAffected(1), nil
+	}
+	tm := NewTransactionManager(db)
+
+	err := tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+		_, err := NamedExecContext(ctx, db, "INSERT INTO users (id, email) VALUES (:id, :email)",
+			namedUser{ID: 7, Email: "user@example.com"})
+		if err != nil {
+			return err
+		}
+		return errInner
+	})
+	if !errors.Is(err, errInner) {
+		t.Fatalf("err = %v, want %v", err, errInner)
+	}
+
+	want := []string{"BEGIN", "INSERT INTO users (id, email) VALUES ($1, $2)", "ROLLBACK"}
+	if got := fake.Statements(); !slices.Equal(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+	if len(args) != 2 || args[0].Value != int64(7) || args[1].Value != "user@example.com" {
+		t.Errorf("args = %+v, want 7 and user@example.com", args)
+	}
+}
+
+func TestNamedSelectOutsideTransaction(t *testing.T) {
+	fake, db := newFakeDB(t)
+	fake.query = func(string, []driver.NamedValue) (driver.Rows, error) {
+		return rowsOf([]string{"id", "email"},
+			[]driver.Value{int64(1), "a@example.com"},
+			[]driver.Value{int64(2), "b@example.com"}), nil
+	}
+
+	var users []namedUser
+	err := NamedSelectContext(context.Background(), db, &users,
+		"SELECT id, email FROM users WHERE email LIKE :pattern", map[string]any{"pattern": "%@example.com"})
+	if err != nil {
+		t.Fatalf("NamedSelectContext: %v", err)
+	}
+
+	if len(users) != 2 || users[1].Email != "b@example.com" {
+		t.Errorf("users = %+v", users)
+	}
+	want := []string{"SELECT id, email FROM users WHERE email LIKE $1"}
+	if got := fake.Statements(); !slices.Equal(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+}
@@ -1,21 +1,23 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"nexus/internal/infrastructure/config"
 	"nexus/pkg/logger"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 )
 
-func NewPostgresConnection(cfg *config.DatabaseSection) (*sqlx.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database, cfg.SSLMode,
-	)
+const (
+	initialConnectBackoff = 500 * time.Millisecond
+	maxConnectBackoff     = 10 * time.Second
+)
 
+func NewPostgresConnection(cfg *config.DatabaseSection) (*sqlx.DB, error) {
 	logger.Info("Connecting to database",
 		slog.String("host", cfg.Host),
 		slog.Int("port", cfg.Port),
@@ -24,14 +26,12 @@ func NewPostgresConnection(cfg *config.DatabaseSection) (*sqlx.DB, error) {
 		slog.String("sslmode", cfg.SSLMode),
 	)
 
-	db, err := sqlx.Connect("postgres", dsn)
+	db, err := connectWithRetry(cfg, sqlx.ConnectContext)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	db.SetMaxOpenConns(cfg.MaxOpenConns)
-	db.SetMaxIdleConns(cfg.MaxIdleConns)
-	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	configurePool(db, cfg)
 
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
@@ -44,3 +44,63 @@ func NewPostgresConnection(cfg *config.DatabaseSection) (*sqlx.DB, error) {
 
 	return db, nil
 }
+
+type connectFunc func(ctx context.Context, driverName, dsn string) (*sqlx.DB, error)
+
+// Retries connect with exponential backoff, bounded by ConnectMaxAttempts
+// and ConnectTimeout. Returns the last error when all attempts fail
+func connectWithRetry(cfg *config.DatabaseSection, connect connectFunc) (*sqlx.DB, error) {
+	maxAttempts := max(cfg.ConnectMaxAttempts, 1)
+
+	ctx := context.Background()
+	if cfg.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.ConnectTimeout)
+		defer cancel()
+	}
+
+	backoff := initialConnectBackoff
+	var lastErr error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		db, err := connect(ctx, "postgres", postgresDSN(cfg))
+		if err == nil {
+			return db, nil
+		}
+		lastErr = err
+
+		logger.Warn("Database connection attempt failed",
+			slog.Int("attempt", attempt),
+			slog.Int("max_attempts", maxAttempts),
+			slog.Any("error", err),
+		)
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("connect timeout after %d attempts: %w", attempt, lastErr)
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+
+	return nil, lastErr
+}
+
+func postgresDSN(cfg *config.DatabaseSection) string {
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database, cfg.SSLMode,
+	)
+}
+
+func configurePool(db *sqlx.DB, cfg *config.DatabaseSection) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+}
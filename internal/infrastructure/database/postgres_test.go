@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"nexus/internal/infrastructure/config"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var errRefused = errors.New("connection refused")
+
+// Fails until the nth call, then returns db
+func succeedOnAttempt(n int, db *sqlx.DB, calls *int) connectFunc {
+	return func(context.Context, string, string) (*sqlx.DB, error) {
+		*calls++
+		if *calls < n {
+			return nil, errRefused
+		}
+		return db, nil
+	}
+}
+
+func TestConnectWithRetrySucceedsOnNthAttempt(t *testing.T) {
+	_, db := newFakeDB(t)
+	var calls int
+
+	got, err := connectWithRetry(&config.DatabaseSection{ConnectMaxAttempts: 5}, succeedOnAttempt(3, db, &calls))
+	if err != nil {
+		t.Fatalf("connectWithRetry: %v", err)
+	}
+	if got != db {
+		t.Error("connectWithRetry returned a different connection")
+	}
+	if calls != 3 {
+		t.Errorf("attempts = %d, want 3", calls)
+	}
+}
+
+func TestConnectWithRetryGivesUp(t *testing.T) {
+	var calls int
+
+	_, err := connectWithRetry(&config.DatabaseSection{ConnectMaxAttempts: 2}, succeedOnAttempt(10, nil, &calls))
+	if !errors.Is(err, errRefused) {
+		t.Errorf("err = %v, want the last connect error", err)
+	}
+	if calls != 2 {
+		t.Errorf("attempts = %d, want 2", calls)
+	}
+}
+
+func TestConnectWithRetryTimeout(t *testing.T) {
+	var calls int
+	cfg := &config.DatabaseSection{ConnectMaxAttempts: 10, ConnectTimeout: 50 * time.Millisecond}
+
+	start := time.Now()
+	_, err := connectWithRetry(cfg, succeedOnAttempt(10, nil, &calls))
+
+	if err == nil || !strings.Contains(err.Error(), "connect timeout") || !errors.Is(err, errRefused) {
+		t.Errorf("err = %v, want a timeout wrapping the last error", err)
+	}
+	if elapsed := time.Since(start); elapsed > initialConnectBackoff {
+		t.Errorf("gave up after %v, want within the connect timeout", elapsed)
+	}
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"nexus/pkg/logger"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
+
+	initialRetryBackoff = 20 * time.Millisecond
+	maxRetryBackoff     = time.Second
+)
+
+// Runs fn in a serializable transaction, re-running it up to maxRetries times
+// when Postgres reports a serialization failure or deadlock. Other errors are
+// returned immediately. Nested calls are not retried, the outer transaction
+// is already aborted in that case and must be retried as a whole
+func (tm *transactionManager) WithTransactionRetry(ctx context.Context, maxRetries int, fn func(ctx context.Context) error) error {
+	opts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+
+	if _, ok := GetTx(ctx); ok {
+		return tm.WithTransactionOpts(ctx, opts, fn)
+	}
+
+	backoff := initialRetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := tm.WithTransactionOpts(ctx, opts, fn)
+		if err == nil || !IsRetryableError(err) || attempt >= maxRetries {
+			return err
+		}
+
+		logger.WarnContext(ctx, "Retrying transaction",
+			slog.Int("attempt", attempt+1),
+			slog.Int("max_retries", maxRetries),
+			slog.Any("error", err))
+
+		// Full jitter spreads out competing transactions
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(rand.N(backoff) + time.Millisecond):
+		}
+
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+}
+
+// Reports whether err is a Postgres serialization failure or deadlock
+func IsRetryableError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == serializationFailureCode || pqErr.Code == deadlockDetectedCode
+}
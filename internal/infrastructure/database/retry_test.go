@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestWithTransactionRetryRetriesSerializationFailures(t *testing.T) {
+	fake, db := newFakeDB(t)
+
+	var calls atomic.Int32
+	fake.exec = func(string, []driver.NamedValue) (driver.Result, error) {
+		if calls.Add(1) <= 2 {
+			return nil, &pq.Error{Code: serializationFailureCode, Message: "could not serialize access"}
+		}
+		return driver.RowsAffected(1), nil
+	}
+	tm := NewTransactionManager(db)
+
+	var runs int
+	err := tm.WithTransactionRetry(context.Background(), 3, func(ctx context.Context) error {
+		runs++
+		_, err := FromContext(ctx, db).ExecContext(ctx, "UPDATE accounts SET balance = balance - 1")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTransactionRetry: %v", err)
+	}
+
+	if runs != 3 {
+		t.Errorf("runs = %d, want 3", runs)
+	}
+	if fake.rollbacks != 2 || fake.commits != 1 {
+		t.Errorf("rollbacks = %d, commits = %d, want 2 and 1", fake.rollbacks, fake.commits)
+	}
+}
+
+func TestWithTransactionRetryGivesUp(t *testing.T) {
+	fake, db := newFakeDB(t)
+	fake.exec = func(string, []driver.NamedValue) (driver.Result, error) {
+		return nil, &pq.Error{Code: deadlockDetectedCode, Message: "deadlock detected"}
+	}
+	tm := NewTransactionManager(db)
+
+	var runs int
+	err := tm.WithTransactionRetry(context.Background(), 2, func(ctx context.Context) error {
+		runs++
+		_, err := FromContext(ctx, db).ExecContext(ctx, "UPDATE accounts SET balance = 0")
+		return err
+	})
+
+	if !IsRetryableError(err) {
+		t.Errorf("err = %v, want the deadlock error", err)
+	}
+	if runs != 3 {
+		t.Errorf("runs = %d, want 1 + 2 retries", runs)
+	}
+}
+
+func TestWithTransactionRetryDoesNotRetryOtherErrors(t *testing.T) {
+	_, db := newFakeDB(t)
+	tm := NewTransactionManager(db)
+	errFailed := errors.New("validation failed")
+
+	var runs int
+	err := tm.WithTransactionRetry(context.Background(), 3, func(context.Context) error {
+		runs++
+		return errFailed
+	})
+
+	if !errors.Is(err, errFailed) || runs != 1 {
+		t.Errorf("err = %v after %d runs, want %v after 1", err, runs, errFailed)
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&pq.Error{Code: serializationFailureCode}, true},
+		{fmt.Errorf("update: %w", &pq.Error{Code: deadlockDetectedCode}), true},
+		{&pq.Error{Code: "23505"}, false},
+		{errors.New("40001"), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRetryableError(tt.err); got != tt.want {
+			t.Errorf("IsRetryableError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nexus/pkg/logger"
+	"reflect"
+	"regexp"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrNullColumn = errors.New("NULL scanned into non-nullable field")
+
+// database/sql doesn't export the scan error type, so the message is parsed
+var nullScanPattern = regexp.MustCompile(`Scan error on column index \d+, name "([^"]+)": converting NULL to (\S+) is unsupported`)
+
+// Scans a single row into dest, inside the ctx transaction if any.
+// NULLs in non-nullable fields fail with ErrNullColumn naming the column
+func GetContext(ctx context.Context, db *sqlx.DB, dest any, query string, args ...any) error {
+	err := FromContext(ctx, db).GetContext(ctx, dest, query, args...)
+	return explainScanError(ctx, err, dest)
+}
+
+// Scans all rows into dest, inside the ctx transaction if any.
+// NULLs in non-nullable fields fail with ErrNullColumn naming the column
+func SelectContext(ctx context.Context, db *sqlx.DB, dest any, query string, args ...any) error {
+	err := FromContext(ctx, db).SelectContext(ctx, dest, query, args...)
+	return explainScanError(ctx, err, dest)
+}
+
+// Replaces "converting NULL to string is unsupported" with an error saying
+// which column and destination need a pointer or sql.Null* type
+func explainScanError(ctx context.Context, err error, dest any) error {
+	if err == nil {
+		return nil
+	}
+
+	match := nullScanPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	column, goType := match[1], match[2]
+
+	logger.FromContext(ctx).Warn("NULL scanned into non-nullable field",
+		"column", column,
+		"type", goType,
+		"dest", fmt.Sprintf("%T", dest))
+
+	return fmt.Errorf("%w: column %q into %s field of %s, use a pointer or sql.Null* type: %v",
+		ErrNullColumn, column, goType, destTypeName(dest), err)
+}
+
+// Element type for slices, e.g. *[]User -> User
+func destTypeName(dest any) string {
+	t := reflect.TypeOf(dest)
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil {
+		return "<nil>"
+	}
+	return t.String()
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type profileRow struct {
+	ID       int64  `db:"id"`
+	Nickname string `db:"nickname"`
+}
+
+type nullableProfileRow struct {
+	ID       int64   `db:"id"`
+	Nickname *string `db:"nickname"`
+}
+
+func queryReturning(values ...[]driver.Value) func(string, []driver.NamedValue) (driver.Rows, error) {
+	return func(string, []driver.NamedValue) (driver.Rows, error) {
+		return rowsOf([]string{"id", "nickname"}, values...), nil
+	}
+}
+
+func TestScanNullIntoNonNullableField(t *testing.T) {
+	buf := captureLogs(t)
+	fake, db := newFakeDB(t)
+	fake.query = queryReturning([]driver.Value{int64(1), nil})
+	ctx := context.Background()
+
+	var row profileRow
+	err := GetContext(ctx, db, &row, "SELECT id, nickname FROM profiles WHERE id = $1", 1)
+	if !errors.Is(err, ErrNullColumn) {
+		t.Fatalf("GetContext err = %v, want %v", err, ErrNullColumn)
+	}
+	if want := `column "nickname" into string field of database.profileRow`; !strings.Contains(err.Error(), want) {
+		t.Errorf("GetContext err = %v, want it to contain %q", err, want)
+	}
+	if !strings.Contains(buf.String(), `"column":"nickname"`) {
+		t.Errorf("logs = %s, want a warning naming the column", buf.String())
+	}
+
+	var rows []profileRow
+	err = SelectContext(ctx, db, &rows, "SELECT id, nickname FROM profiles")
+	if !errors.Is(err, ErrNullColumn) || !strings.Contains(err.Error(), "of database.profileRow") {
+		t.Errorf("SelectContext err = %v, want %v naming the element type", err, ErrNullColumn)
+	}
+}
+
+func TestScanNullIntoPointerField(t *testing.T) {
+	fake, db := newFakeDB(t)
+	fake.query = queryReturning([]driver.Value{int64(1), nil}, []driver.Value{int64(2), "neo"})
+
+	var rows []nullableProfileRow
+	if err := SelectContext(context.Background(), db, &rows, "SELECT id, nickname FROM profiles"); err != nil {
+		t.Fatalf("SelectContext: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Nickname != nil || rows[1].Nickname == nil || *rows[1].Nickname != "neo" {
+		t.Errorf("rows = %+v, want a nil and a set nickname", rows)
+	}
+}
+
+func TestExplainScanErrorPassesOtherErrors(t *testing.T) {
+	other := errors.New("connection reset")
+	if err := explainScanError(context.Background(), other, &profileRow{}); err != other {
+		t.Errorf("explainScanError = %v, want %v unchanged", err, other)
+	}
+}
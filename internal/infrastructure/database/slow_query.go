@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"nexus/pkg/logger"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const maxLoggedQueryLength = 200
+
+// Executor that logs queries slower than threshold, opt-in per call site:
+//
+//	exec := database.WithSlowQueryLog(database.FromContext(ctx, db), 200*time.Millisecond)
+type slowQueryExecutor struct {
+	exec      Executor
+	threshold time.Duration
+}
+
+func WithSlowQueryLog(exec Executor, threshold time.Duration) Executor {
+	return &slowQueryExecutor{
+		exec:      exec,
+		threshold: threshold,
+	}
+}
+
+func (e *slowQueryExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	defer e.observe(ctx, query, time.Now())
+	return e.exec.ExecContext(ctx, query, args...)
+}
+
+// Measures only the time to the first row, iterating is up to the caller
+func (e *slowQueryExecutor) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	defer e.observe(ctx, query, time.Now())
+	return e.exec.QueryxContext(ctx, query, args...)
+}
+
+func (e *slowQueryExecutor) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	defer e.observe(ctx, query, time.Now())
+	return e.exec.GetContext(ctx, dest, query, args...)
+}
+
+func (e *slowQueryExecutor) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	defer e.observe(ctx, query, time.Now())
+	return e.exec.SelectContext(ctx, dest, query, args...)
+}
+
+func (e *slowQueryExecutor) observe(ctx context.Context, query string, start time.Time) {
+	duration := time.Since(start)
+	if duration < e.threshold {
+		return
+	}
+
+	logger.FromContext(ctx).Warn("Slow query",
+		slog.String("query", truncateQuery(query)),
+		slog.Duration("duration", duration),
+		slog.Duration("threshold", e.threshold))
+}
+
+// Collapses whitespace so multi-line queries fit on one log line
+func truncateQuery(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxLoggedQueryLength {
+		return query[:maxLoggedQueryLength] + "..."
+	}
+	return query
+}
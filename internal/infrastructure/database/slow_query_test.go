@@ -0,0 +1,73 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"nexus/pkg/logger"
+	"strings"
+	"testing"
+	"time"
+)
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger.Init(logger.Config{Format: "json", Output: &buf})
+	t.Cleanup(func() { logger.Init(logger.Config{Output: io.Discard}) })
+	return &buf
+}
+
+func TestSlowQueryLogsWarning(t *testing.T) {
+	buf := captureLogs(t)
+	fake, db := newFakeDB(t)
+	fake.exec = func(string, []driver.NamedValue) (driver.Result, error) {
+		time.Sleep(20 * time.Millisecond)
+		return driver.RowsAffected(1), nil
+	}
+
+	exec := WithSlowQueryLog(db, 5*time.Millisecond)
+	if _, err := exec.ExecContext(context.Background(), "UPDATE users\n\tSET active = false"); err != nil {
+		t.Fatalf("ExecContext: %v", err)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode log %q: %v", buf.String(), err)
+	}
+	if record["level"] != "WARN" || record["msg"] != "Slow query" {
+		t.Errorf("record = %v, want a Slow query warning", record)
+	}
+	if record["query"] != "UPDATE users SET active = false" {
+		t.Errorf("query = %v, want the collapsed SQL", record["query"])
+	}
+	if _, ok := record["duration"]; !ok {
+		t.Error("duration missing from the log record")
+	}
+}
+
+func TestFastQueryIsNotLogged(t *testing.T) {
+	buf := captureLogs(t)
+	_, db := newFakeDB(t)
+
+	exec := WithSlowQueryLog(db, time.Second)
+	if _, err := exec.ExecContext(context.Background(), "UPDATE users SET active = false"); err != nil {
+		t.Fatalf("ExecContext: %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("logged %q for a fast query", buf.String())
+	}
+}
+
+func TestTruncateQuery(t *testing.T) {
+	long := "SELECT " + strings.Repeat("x, ", 100) + "y FROM t"
+
+	got := truncateQuery(long)
+	if len(got) != maxLoggedQueryLength+len("...") || !strings.HasSuffix(got, "...") {
+		t.Errorf("truncateQuery = %q (%d bytes), want %d bytes and an ellipsis", got, len(got), maxLoggedQueryLength+3)
+	}
+}
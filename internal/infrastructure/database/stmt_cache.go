@@ -0,0 +1,260 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	invalidStatementNameCode = "26000"
+	featureNotSupportedCode  = "0A000" // "cached plan must not change result type" after a schema change
+
+	defaultStmtCacheSize = 256
+)
+
+// Executor that prepares each distinct query once and reuses the statement.
+// database/sql re-prepares statements transparently on other pool connections,
+// so one *sqlx.Stmt per query is safe for concurrent use. Runs inside the ctx
+// transaction if any. Statements invalidated by schema changes are dropped and
+// re-prepared. A disabled cache runs every query directly on the pool
+type StmtCache struct {
+	db       *sqlx.DB
+	disabled bool
+	maxSize  int
+
+	mu    sync.Mutex
+	stmts map[string]*cachedStmt
+}
+
+// Statement with the number of callers running it. An evicted statement
+// stays open until the last of them releases it
+type cachedStmt struct {
+	stmt    *sqlx.Stmt
+	refs    int
+	evicted bool
+}
+
+type StmtCacheOption func(*StmtCache)
+
+// Runs every query unprepared, e.g. behind PgBouncer in transaction mode
+func WithStmtCacheDisabled(disabled bool) StmtCacheOption {
+	return func(c *StmtCache) {
+		c.disabled = disabled
+	}
+}
+
+// Queries beyond size are run unprepared instead of growing the cache
+func WithStmtCacheSize(size int) StmtCacheOption {
+	return func(c *StmtCache) {
+		c.maxSize = size
+	}
+}
+
+func NewStmtCache(db *sqlx.DB, opts ...StmtCacheOption) *StmtCache {
+	c := &StmtCache{
+		db:      db,
+		maxSize: defaultStmtCacheSize,
+		stmts:   make(map[string]*cachedStmt),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *StmtCache) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var result sql.Result
+	err := c.run(ctx, query, func(stmt *sqlx.Stmt) error {
+		var err error
+		result, err = stmt.ExecContext(ctx, args...)
+		return err
+	}, func(exec Executor) error {
+		var err error
+		result, err = exec.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+func (c *StmtCache) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	var rows *sqlx.Rows
+	err := c.run(ctx, query, func(stmt *sqlx.Stmt) error {
+		var err error
+		rows, err = stmt.QueryxContext(ctx, args...)
+		return err
+	}, func(exec Executor) error {
+		var err error
+		rows, err = exec.QueryxContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (c *StmtCache) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	return c.run(ctx, query, func(stmt *sqlx.Stmt) error {
+		return stmt.GetContext(ctx, dest, args...)
+	}, func(exec Executor) error {
+		return exec.GetContext(ctx, dest, query, args...)
+	})
+}
+
+func (c *StmtCache) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	return c.run(ctx, query, func(stmt *sqlx.Stmt) error {
+		return stmt.SelectContext(ctx, dest, args...)
+	}, func(exec Executor) error {
+		return exec.SelectContext(ctx, dest, query, args...)
+	})
+}
+
+// Closes all cached statements, the cache stays usable and re-prepares on demand.
+// Statements still running close once their callers are done
+func (c *StmtCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for query, cached := range c.stmts {
+		errs = append(errs, c.evictLocked(query, cached))
+	}
+	return errors.Join(errs...)
+}
+
+// Runs withStmt on the cached statement, or direct when caching isn't possible
+func (c *StmtCache) run(ctx context.Context, query string, withStmt func(*sqlx.Stmt) error, direct func(Executor) error) error {
+	if c.disabled {
+		return direct(FromContext(ctx, c.db))
+	}
+
+	cached, err := c.acquire(ctx, query)
+	if err != nil {
+		return err
+	}
+	if cached == nil {
+		return direct(FromContext(ctx, c.db))
+	}
+
+	tx, inTx := GetTx(ctx)
+	if inTx {
+		// Bound to the transaction's connection, closed with it
+		err = withStmt(tx.StmtxContext(ctx, cached.stmt))
+		c.release(cached)
+		return err
+	}
+
+	err = withStmt(cached.stmt)
+	if !isStaleStatementError(err) {
+		c.release(cached)
+		return err
+	}
+
+	// Schema changed under the statement: prepare again and retry once.
+	// Not possible inside a transaction, Postgres aborts it on the error
+	c.evict(query, cached)
+	c.release(cached)
+
+	if cached, err = c.acquire(ctx, query); err != nil {
+		return err
+	}
+	if cached == nil {
+		return direct(c.db)
+	}
+	defer c.release(cached)
+
+	return withStmt(cached.stmt)
+}
+
+// Returns the cached statement with a reference held, release it when done.
+// Returns nil without an error when the cache is full
+func (c *StmtCache) acquire(ctx context.Context, query string) (*cachedStmt, error) {
+	c.mu.Lock()
+	cached, ok := c.stmts[query]
+	if ok {
+		cached.refs++
+	}
+	full := len(c.stmts) >= c.maxSize
+	c.mu.Unlock()
+
+	if ok {
+		return cached, nil
+	}
+	if full {
+		return nil, nil
+	}
+
+	// Prepared outside the lock, a concurrent prepare of the same query may win
+	prepared, err := c.db.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, ok := c.stmts[query]; ok {
+		prepared.Close()
+		existing.refs++
+		return existing, nil
+	}
+	if len(c.stmts) >= c.maxSize {
+		prepared.Close()
+		return nil, nil
+	}
+
+	cached = &cachedStmt{stmt: prepared, refs: 1}
+	c.stmts[query] = cached
+	return cached, nil
+}
+
+func (c *StmtCache) release(cached *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached.refs--
+	if cached.evicted && cached.refs == 0 {
+		cached.stmt.Close()
+	}
+}
+
+// Only removes cached if it's still the cached one, another caller may have replaced it
+func (c *StmtCache) evict(query string, cached *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stmts[query] == cached {
+		c.evictLocked(query, cached)
+	}
+}
+
+// Drops the statement from the cache, closing it right away if nobody runs it
+func (c *StmtCache) evictLocked(query string, cached *cachedStmt) error {
+	delete(c.stmts, query)
+	cached.evicted = true
+
+	if cached.refs == 0 {
+		return cached.stmt.Close()
+	}
+	return nil
+}
+
+func isStaleStatementError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	switch string(pqErr.Code) {
+	case invalidStatementNameCode:
+		return true
+	case featureNotSupportedCode:
+		return strings.Contains(pqErr.Message, "cached plan must not change result type")
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const countQuery = "SELECT count(*) FROM users WHERE active = $1"
+
+func countRows(string, []driver.NamedValue) (driver.Rows, error) {
+	return rowsOf([]string{"count"}, []driver.Value{int64(42)}), nil
+}
+
+func TestStmtCacheReusesPreparedStatement(t *testing.T) {
+	fake, db := newFakeDB(t)
+	fake.query = countRows
+	cache := NewStmtCache(db)
+	ctx := context.Background()
+
+	for range 5 {
+		var count int
+		if err := cache.GetContext(ctx, &count, countQuery, true); err != nil {
+			t.Fatalf("GetContext: %v", err)
+		}
+		if count != 42 {
+			t.Fatalf("count = %d, want 42", count)
+		}
+	}
+
+	if got := fake.Prepares(); got != 1 {
+		t.Errorf("prepares = %d, want 1", got)
+	}
+}
+
+func TestStmtCacheDisabled(t *testing.T) {
+	fake, db := newFakeDB(t)
+	fake.query = countRows
+	cache := NewStmtCache(db, WithStmtCacheDisabled(true))
+
+	var count int
+	if err := cache.GetContext(context.Background(), &count, countQuery, true); err != nil {
+		t.Fatalf("GetContext: %v", err)
+	}
+
+	if got := fake.Prepares(); got != 0 {
+		t.Errorf("prepares = %d, want 0", got)
+	}
+}
+
+func TestStmtCacheFullRunsUnprepared(t *testing.T) {
+	fake, db := newFakeDB(t)
+	cache := NewStmtCache(db, WithStmtCacheSize(1))
+	ctx := context.Background()
+
+	for _, query := range []string{"DELETE FROM a", "DELETE FROM b", "DELETE FROM b"} {
+		if _, err := cache.ExecContext(ctx, query); err != nil {
+			t.Fatalf("ExecContext(%q): %v", query, err)
+		}
+	}
+
+	if got := fake.Prepares(); got != 1 {
+		t.Errorf("prepares = %d, want 1", got)
+	}
+}
+
+func TestStmtCacheReprepareAfterSchemaChange(t *testing.T) {
+	fake, db := newFakeDB(t)
+
+	var calls atomic.Int32
+	fake.exec = func(string, []driver.NamedValue) (driver.Result, error) {
+		if calls.Add(1) == 1 {
+			return nil, &pq.Error{Code: "0A000", Message: "cached plan must not change result type"}
+		}
+		return driver.RowsAffected(1), nil
+	}
+	cache := NewStmtCache(db)
+
+	result, err := cache.ExecContext(context.Background(), "UPDATE users SET active = $1", false)
+	if err != nil {
+		t.Fatalf("ExecContext: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n != 1 {
+		t.Errorf("rows affected = %d, want 1", n)
+	}
+
+	if got := fake.Prepares(); got != 2 {
+		t.Errorf("prepares = %d, want 2", got)
+	}
+	if got := fake.StmtCloses(); got != 1 {
+		t.Errorf("closed statements = %d, want the stale one only", got)
+	}
+}
+
+func TestStmtCacheEvictedStatementClosesAfterLastUser(t *testing.T) {
+	fake, db := newFakeDB(t)
+	cache := NewStmtCache(db)
+	ctx := context.Background()
+
+	cached, err := cache.acquire(ctx, countQuery)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := fake.StmtCloses(); got != 0 {
+		t.Fatalf("statement closed while in use")
+	}
+
+	fake.query = countRows
+	var count int
+	if err := cached.stmt.GetContext(ctx, &count, true); err != nil {
+		t.Fatalf("evicted statement unusable before release: %v", err)
+	}
+
+	cache.release(cached)
+	if got := fake.StmtCloses(); got != 1 {
+		t.Errorf("closed statements after release = %d, want 1", got)
+	}
+}
+
+func TestStmtCacheConcurrentCloseDoesNotFailQueries(t *testing.T) {
+	fake, db := newFakeDB(t)
+	fake.query = countRows
+	cache := NewStmtCache(db)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				var count int
+				if err := cache.GetContext(ctx, &count, countQuery, true); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				cache.Close()
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(stop)
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("query failed while the cache was closed concurrently: %v", err)
+	}
+}
+
+func TestStmtCacheInTransaction(t *testing.T) {
+	fake, db := newFakeDB(t)
+	cache := NewStmtCache(db)
+	tm := NewTransactionManager(db)
+
+	err := tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+		_, err := cache.ExecContext(ctx, "DELETE FROM sessions")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction: %v", err)
+	}
+
+	want := []string{"BEGIN", "DELETE FROM sessions", "COMMIT"}
+	if got := fake.Statements(); !slices.Equal(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+}
+
+// Against Postgres when NEXUS_TEST_DATABASE_URL is set, where the cached
+// variant skips parsing and planning. The fake driver only measures overhead
+func BenchmarkStmtCache(b *testing.B) {
+	db := benchmarkDB(b)
+	ctx := context.Background()
+	const query = "SELECT $1::int + 1"
+
+	run := func(b *testing.B, exec Executor) {
+		for b.Loop() {
+			rows, err := exec.QueryxContext(ctx, query, 1)
+			if err != nil {
+				b.Fatal(err)
+			}
+			rows.Close()
+		}
+	}
+
+	b.Run("cached", func(b *testing.B) {
+		cache := NewStmtCache(db)
+		defer cache.Close()
+		run(b, cache)
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		run(b, NewStmtCache(db, WithStmtCacheDisabled(true)))
+	})
+}
+
+func benchmarkDB(b *testing.B) *sqlx.DB {
+	dsn := os.Getenv("NEXUS_TEST_DATABASE_URL")
+	if dsn == "" {
+		_, db := newFakeDB(b)
+		return db
+	}
+
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		b.Fatalf("connect: %v", err)
+	}
+	b.Cleanup(func() { db.Close() })
+	return db
+}
@@ -3,15 +3,33 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type TransactionManager interface {
+	// Runs fn in a transaction. When ctx already carries a transaction,
+	// fn runs in a savepoint of it instead, so an inner failure only
+	// rolls back the inner work
 	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	// Like WithTransaction with custom isolation level / read-only flag.
+	// Nested calls run in a savepoint, which inherits the outer options, so a
+	// read-only nested call inside a writable transaction fails with
+	// ErrReadOnlyNested
+	WithTransactionOpts(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error
+	// Serializable transaction retried on serialization failures and deadlocks
+	WithTransactionRetry(ctx context.Context, maxRetries int, fn func(ctx context.Context) error) error
+	// Read-only repeatable read transaction, writes inside fn fail.
+	// Nested in a read-only transaction it runs in a savepoint, nested in a
+	// writable one it fails with ErrReadOnlyNested instead of allowing writes
+	WithReadOnlyTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
+// A savepoint can't be made read-only, the outer transaction decides
+var ErrReadOnlyNested = errors.New("read-only transaction requested inside a writable transaction")
+
 type transactionManager struct {
 	db *sqlx.DB
 }
@@ -24,15 +42,56 @@ type ctxKey string
 
 const txKey ctxKey = "tx"
 
+// Transaction bound to context with its nesting depth
+type txState struct {
+	tx       *sqlx.Tx
+	depth    int
+	readOnly bool
+	// Registered at this level, handed to the parent when a savepoint is released
+	afterCommit []func()
+}
+
+// Runs fn once the transaction in ctx commits, e.g. to publish events.
+// Callbacks are dropped when the transaction or the savepoint they were
+// registered in rolls back. Without a transaction fn runs immediately
+func RegisterAfterCommit(ctx context.Context, fn func()) {
+	state, ok := ctx.Value(txKey).(*txState)
+	if !ok {
+		fn()
+		return
+	}
+	state.afterCommit = append(state.afterCommit, fn)
+}
+
 func (tm *transactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	tx, err := tm.db.BeginTxx(ctx, &sql.TxOptions{
+	return tm.WithTransactionOpts(ctx, &sql.TxOptions{
 		Isolation: sql.LevelReadCommitted,
-	})
+	}, fn)
+}
+
+// Consistent snapshot for reports, any stray write fails
+func (tm *transactionManager) WithReadOnlyTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return tm.WithTransactionOpts(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	}, fn)
+}
+
+func (tm *transactionManager) WithTransactionOpts(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	if parent, ok := ctx.Value(txKey).(*txState); ok {
+		if opts != nil && opts.ReadOnly && !parent.readOnly {
+			return ErrReadOnlyNested
+		}
+		return withSavepoint(ctx, parent, fn)
+	}
+
+	tx, err := tm.db.BeginTxx(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("begin transaction:  %w", err)
 	}
 
-	ctx = context.WithValue(ctx, txKey, tx)
+	state := &txState{tx: tx, readOnly: opts != nil && opts.ReadOnly}
+	ctx = context.WithValue(ctx, txKey, state)
 
 	err = fn(ctx)
 	if err != nil {
@@ -46,10 +105,43 @@ func (tm *transactionManager) WithTransaction(ctx context.Context, fn func(ctx c
 		return fmt.Errorf("commit transaction: %w", err)
 	}
 
+	for _, callback := range state.afterCommit {
+		callback()
+	}
+
+	return nil
+}
+
+func withSavepoint(ctx context.Context, parent *txState, fn func(ctx context.Context) error) error {
+	state := &txState{tx: parent.tx, depth: parent.depth + 1, readOnly: parent.readOnly}
+	savepoint := fmt.Sprintf("sp_%d", state.depth)
+
+	if _, err := state.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("create savepoint: %w", err)
+	}
+
+	err := fn(context.WithValue(ctx, txKey, state))
+	if err != nil {
+		if _, rbErr := state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); rbErr != nil {
+			return fmt.Errorf("rollback to savepoint: %v (original error: %w)", rbErr, err)
+		}
+		return err
+	}
+
+	if _, err := state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("release savepoint: %w", err)
+	}
+
+	// Released work commits with the parent, so do its callbacks
+	parent.afterCommit = append(parent.afterCommit, state.afterCommit...)
+
 	return nil
 }
 
 func GetTx(ctx context.Context) (*sqlx.Tx, bool) {
-	tx, ok := ctx.Value(txKey).(*sqlx.Tx)
-	return tx, ok
+	state, ok := ctx.Value(txKey).(*txState)
+	if !ok {
+		return nil, false
+	}
+	return state.tx, true
 }
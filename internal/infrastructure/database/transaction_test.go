@@ -0,0 +1,266 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+var errInner = errors.New("inner failed")
+
+func TestNestedTransactionRollsBackOnlyInnerWork(t *testing.T) {
+	fake, db := newFakeDB(t)
+	tm := NewTransactionManager(db)
+
+	err := tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+		if _, err := FromContext(ctx, db).ExecContext(ctx, "INSERT INTO orders"); err != nil {
+			return err
+		}
+
+		err := tm.WithTransaction(ctx, func(ctx context.Context) error {
+			if _, err := FromContext(ctx, db).ExecContext(ctx, "INSERT INTO audit_log"); err != nil {
+				return err
+			}
+			return errInner
+		})
+		if !errors.Is(err, errInner) {
+			t.Errorf("inner err = %v, want %v", err, errInner)
+		}
+
+		_, err = FromContext(ctx, db).ExecContext(ctx, "UPDATE stock")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction: %v", err)
+	}
+
+	want := []string{
+		"BEGIN",
+		"INSERT INTO orders",
+		"SAVEPOINT sp_1",
+		"INSERT INTO audit_log",
+		"ROLLBACK TO SAVEPOINT sp_1",
+		"UPDATE stock",
+		"COMMIT",
+	}
+	if got := fake.Statements(); !slices.Equal(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+}
+
+func TestNestedTransactionReleasesSavepoints(t *testing.T) {
+	fake, db := newFakeDB(t)
+	tm := NewTransactionManager(db)
+
+	err := tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+		return tm.WithTransaction(ctx, func(ctx context.Context) error {
+			return tm.WithTransaction(ctx, func(context.Context) error { return nil })
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction: %v", err)
+	}
+
+	want := []string{"BEGIN", "SAVEPOINT sp_1", "SAVEPOINT sp_2", "RELEASE SAVEPOINT sp_2", "RELEASE SAVEPOINT sp_1", "COMMIT"}
+	if got := fake.Statements(); !slices.Equal(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+}
+
+func TestOuterFailureRollsBackEverything(t *testing.T) {
+	fake, db := newFakeDB(t)
+	tm := NewTransactionManager(db)
+
+	err := tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+		if err := tm.WithTransaction(ctx, func(context.Context) error { return nil }); err != nil {
+			return err
+		}
+		return errInner
+	})
+	if !errors.Is(err, errInner) {
+		t.Fatalf("err = %v, want %v", err, errInner)
+	}
+
+	if fake.commits != 0 || fake.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want 0 and 1", fake.commits, fake.rollbacks)
+	}
+}
+
+func TestWithTransactionOptsBeginsWithOptions(t *testing.T) {
+	fake, db := newFakeDB(t)
+	tm := NewTransactionManager(db)
+	ctx := context.Background()
+
+	noop := func(context.Context) error { return nil }
+	if err := tm.WithTransactionOpts(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable}, noop); err != nil {
+		t.Fatalf("WithTransactionOpts: %v", err)
+	}
+	if err := tm.WithTransaction(ctx, noop); err != nil {
+		t.Fatalf("WithTransaction: %v", err)
+	}
+
+	want := []driver.TxOptions{
+		{Isolation: driver.IsolationLevel(sql.LevelSerializable)},
+		{Isolation: driver.IsolationLevel(sql.LevelReadCommitted)},
+	}
+	if !slices.Equal(fake.begins, want) {
+		t.Errorf("begun with %+v, want %+v", fake.begins, want)
+	}
+}
+
+func TestAfterCommitCallbacks(t *testing.T) {
+	tests := []struct {
+		name     string
+		rollback bool
+		want     []string
+	}{
+		{"commit", false, []string{"outer", "released"}},
+		{"rollback", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			tm := NewTransactionManager(db)
+
+			var fired []string
+			record := func(name string) func() {
+				return func() {
+					// Callbacks only see a finished transaction
+					if stmts := fake.Statements(); stmts[len(stmts)-1] != "COMMIT" {
+						t.Errorf("%s fired before COMMIT, statements = %q", name, stmts)
+					}
+					fired = append(fired, name)
+				}
+			}
+
+			err := tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+				RegisterAfterCommit(ctx, record("outer"))
+
+				_ = tm.WithTransaction(ctx, func(ctx context.Context) error {
+					RegisterAfterCommit(ctx, record("released"))
+					return nil
+				})
+				_ = tm.WithTransaction(ctx, func(ctx context.Context) error {
+					RegisterAfterCommit(ctx, record("rolled back savepoint"))
+					return errInner
+				})
+
+				if len(fired) != 0 {
+					t.Errorf("callbacks fired inside the transaction: %q", fired)
+				}
+				if tt.rollback {
+					return errInner
+				}
+				return nil
+			})
+			if tt.rollback != errors.Is(err, errInner) {
+				t.Fatalf("WithTransaction err = %v", err)
+			}
+
+			if !slices.Equal(fired, tt.want) {
+				t.Errorf("fired = %q, want %q", fired, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterAfterCommitWithoutTransaction(t *testing.T) {
+	fired := false
+	RegisterAfterCommit(context.Background(), func() { fired = true })
+
+	if !fired {
+		t.Error("callback outside a transaction did not run immediately")
+	}
+}
+
+func TestReadOnlyTransactionRejectsWrites(t *testing.T) {
+	fake, db := newFakeDB(t)
+	tm := NewTransactionManager(db)
+
+	var writeErr error
+	err := tm.WithReadOnlyTransaction(context.Background(), func(ctx context.Context) error {
+		if _, err := FromContext(ctx, db).ExecContext(ctx, "SELECT count(*) FROM orders"); err != nil {
+			t.Errorf("read inside read-only transaction: %v", err)
+		}
+
+		_, writeErr = FromContext(ctx, db).ExecContext(ctx, "INSERT INTO orders (id) VALUES ($1)", 1)
+		return writeErr
+	})
+
+	var pqErr *pq.Error
+	if !errors.As(writeErr, &pqErr) || pqErr.Code != "25006" {
+		t.Fatalf("INSERT err = %v, want read_only_sql_transaction (25006)", writeErr)
+	}
+	if !errors.Is(err, writeErr) {
+		t.Errorf("WithReadOnlyTransaction err = %v, want the write error", err)
+	}
+
+	want := []driver.TxOptions{{Isolation: driver.IsolationLevel(sql.LevelRepeatableRead), ReadOnly: true}}
+	if !slices.Equal(fake.begins, want) {
+		t.Errorf("begun with %+v, want %+v", fake.begins, want)
+	}
+	if fake.rollbacks != 1 || fake.commits != 0 {
+		t.Errorf("commits/rollbacks = %d/%d, want 0/1", fake.commits, fake.rollbacks)
+	}
+}
+
+func TestReadOnlyTransactionNestedInWritable(t *testing.T) {
+	fake, db := newFakeDB(t)
+	tm := NewTransactionManager(db)
+
+	ran := false
+	err := tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+		err := tm.WithReadOnlyTransaction(ctx, func(ctx context.Context) error {
+			ran = true
+			_, err := FromContext(ctx, db).ExecContext(ctx, "INSERT INTO orders (id) VALUES ($1)", 1)
+			return err
+		})
+		if !errors.Is(err, ErrReadOnlyNested) {
+			t.Errorf("nested WithReadOnlyTransaction err = %v, want %v", err, ErrReadOnlyNested)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction: %v", err)
+	}
+
+	if ran {
+		t.Error("read-only fn ran inside a writable transaction")
+	}
+	if want := []string{"BEGIN", "COMMIT"}; !slices.Equal(fake.Statements(), want) {
+		t.Errorf("statements = %q, want %q", fake.Statements(), want)
+	}
+}
+
+func TestReadOnlyTransactionNestedInReadOnly(t *testing.T) {
+	fake, db := newFakeDB(t)
+	tm := NewTransactionManager(db)
+
+	var writeErr error
+	err := tm.WithReadOnlyTransaction(context.Background(), func(ctx context.Context) error {
+		return tm.WithReadOnlyTransaction(ctx, func(ctx context.Context) error {
+			// Writable nested calls stay read-only too, the savepoint inherits it
+			return tm.WithTransaction(ctx, func(ctx context.Context) error {
+				_, writeErr = FromContext(ctx, db).ExecContext(ctx, "INSERT INTO orders (id) VALUES ($1)", 1)
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithReadOnlyTransaction: %v", err)
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(writeErr, &pqErr) || pqErr.Code != "25006" {
+		t.Errorf("INSERT err = %v, want read_only_sql_transaction (25006)", writeErr)
+	}
+	if len(fake.begins) != 1 || !fake.begins[0].ReadOnly {
+		t.Errorf("begun with %+v, want one read-only transaction", fake.begins)
+	}
+}
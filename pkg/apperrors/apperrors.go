@@ -0,0 +1,15 @@
+package apperrors
+
+import "errors"
+
+// Sentinel errors shared by services and repositories. Wrap them with
+// fmt.Errorf("...: %w", ErrNotFound) to add context, the HTTP layer maps
+// them to status codes with errors.Is
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrValidation      = errors.New("validation failed")
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrForbidden       = errors.New("forbidden")
+	ErrBadRequest      = errors.New("bad request")
+)
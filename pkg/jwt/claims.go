@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Claim names Extra can't use, they are set by the manager
+var reservedClaims = map[string]bool{
+	"iss": true, "sub": true, "aud": true, "exp": true, "nbf": true, "iat": true, "jti": true,
+	"user_id": true, "email": true, "roles": true, "scopes": true, "token_type": true,
+}
+
+// Without the methods, so encoding/json doesn't recurse into ours
+type claimsFields Claims
+
+// Flattens Extra into the top level of the payload
+func (c Claims) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(claimsFields(c))
+	if err != nil || len(c.Extra) == 0 {
+		return data, err
+	}
+
+	payload := make(map[string]any)
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	for name, value := range c.Extra {
+		if !reservedClaims[name] {
+			payload[name] = value
+		}
+	}
+
+	return json.Marshal(payload)
+}
+
+// Collects claims without a struct field into Extra
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	var fields claimsFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+	for name := range reservedClaims {
+		delete(payload, name)
+	}
+
+	fields.Extra = nil
+	if len(payload) > 0 {
+		fields.Extra = payload
+	}
+
+	*c = Claims(fields)
+	return nil
+}
+
+// Time left until exp, 0 when expired or exp is not set
+func (c *Claims) RemainingTTL() time.Duration {
+	if c.ExpiresAt == nil {
+		return 0
+	}
+	return max(time.Until(c.ExpiresAt.Time), 0)
+}
+
+// Zero time when iat is not set
+func (c *Claims) IssuedAtTime() time.Time {
+	if c.IssuedAt == nil {
+		return time.Time{}
+	}
+	return c.IssuedAt.Time
+}
@@ -0,0 +1,140 @@
+package jwt
+
+import (
+	"errors"
+	"nexus/pkg/uuidv7"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestExtraClaimsRoundTrip(t *testing.T) {
+	m := newTestManager()
+	extra := map[string]any{
+		"tenant_id": "tenant-42",
+		"features":  []any{"beta-reports"},
+		"max_seats": float64(25), // JSON numbers decode as float64
+	}
+
+	pair, err := m.GenerateTokenPairWithExtra(uuidv7.New(), "user@example.com", extra)
+	if err != nil {
+		t.Fatalf("GenerateTokenPairWithExtra: %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		token     string
+		tokenType TokenType
+	}{
+		"access":  {pair.AccessToken, TokenTypeAccess},
+		"refresh": {pair.RefreshToken, TokenTypeRefresh},
+	} {
+		t.Run(name, func(t *testing.T) {
+			claims, err := m.ValidateToken(tc.token, tc.tokenType)
+			if err != nil {
+				t.Fatalf("ValidateToken: %v", err)
+			}
+			if !reflect.DeepEqual(claims.Extra, extra) {
+				t.Errorf("Extra = %v, want %v", claims.Extra, extra)
+			}
+			if claims.ExpiresAt == nil || claims.UserID == uuidv7.Nil {
+				t.Errorf("registered claims lost next to extras: %+v", claims)
+			}
+		})
+	}
+}
+
+func TestExtraClaimsWithoutExtras(t *testing.T) {
+	m := newTestManager()
+
+	token, _, err := m.GenerateAccessToken(uuidv7.New(), "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	claims, err := m.ValidateToken(token, TokenTypeAccess)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if len(claims.Extra) != 0 {
+		t.Errorf("Extra = %v, want empty", claims.Extra)
+	}
+}
+
+func TestExtraClaimsRejectReservedNames(t *testing.T) {
+	m := newTestManager()
+
+	for _, name := range []string{"exp", "sub", "iss", "user_id", "token_type", "roles"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := m.GenerateTokenPairWithExtra(uuidv7.New(), "user@example.com", map[string]any{name: "x"})
+			if !errors.Is(err, ErrReservedClaim) {
+				t.Errorf("err = %v, want %v", err, ErrReservedClaim)
+			}
+		})
+	}
+}
+
+func TestRemainingTTLAndIssuedAt(t *testing.T) {
+	m := newTestManager(WithLeeway(time.Minute))
+	now := time.Now()
+
+	pair, err := m.GenerateTokenPair(uuidv7.New(), "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+
+	nearExpiry := signClaims(t, Claims{
+		UserID: uuidv7.New(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now.Add(-15 * time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Second)),
+		},
+	})
+	// Still accepted thanks to the leeway
+	expired := signClaims(t, Claims{
+		UserID: uuidv7.New(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(-10 * time.Second)),
+		},
+	})
+
+	tests := []struct {
+		name     string
+		token    string
+		minTTL   time.Duration
+		maxTTL   time.Duration
+		issuedAt time.Time
+	}{
+		{"fresh", pair.AccessToken, 14 * time.Minute, 15 * time.Minute, now},
+		{"near expiry", nearExpiry, 3 * time.Second, 5 * time.Second, now.Add(-15 * time.Minute)},
+		{"expired within leeway", expired, 0, 0, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := m.ValidateToken(tt.token, TokenTypeAccess)
+			if err != nil {
+				t.Fatalf("ValidateToken: %v", err)
+			}
+
+			if ttl := claims.RemainingTTL(); ttl < tt.minTTL || ttl > tt.maxTTL {
+				t.Errorf("RemainingTTL = %v, want between %v and %v", ttl, tt.minTTL, tt.maxTTL)
+			}
+			// iat has second precision
+			if got := claims.IssuedAtTime(); got.Sub(tt.issuedAt).Abs() > time.Second {
+				t.Errorf("IssuedAtTime = %v, want %v", got, tt.issuedAt)
+			}
+		})
+	}
+}
+
+func TestRemainingTTLWithoutExpiry(t *testing.T) {
+	var claims Claims
+	if ttl := claims.RemainingTTL(); ttl != 0 {
+		t.Errorf("RemainingTTL = %v, want 0", ttl)
+	}
+	if iat := claims.IssuedAtTime(); !iat.IsZero() {
+		t.Errorf("IssuedAtTime = %v, want zero", iat)
+	}
+}
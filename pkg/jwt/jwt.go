@@ -1,21 +1,95 @@
 package jwt
 
 import (
+	"context"
+	"crypto/rsa"
 	"errors"
+	"fmt"
+	"log/slog"
+	"nexus/pkg/logger"
 	"nexus/pkg/uuidv7"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 var (
-	ErrInvalidToken = errors.New("invalid token")
-	ErrExpiredToken = errors.New("token has expired")
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrExpiredToken     = errors.New("token has expired")
+	ErrTokenNotYetValid = errors.New("token is not valid yet")
+	ErrRevokedToken     = errors.New("token has been revoked")
+
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+
+	ErrInvalidIssuer   = errors.New("token issuer is not accepted")
+	ErrInvalidAudience = errors.New("token audience is not accepted")
+
+	ErrMissingSigningKey       = errors.New("signing key is not configured")
+	ErrUnknownKeyID            = errors.New("unknown signing key id")
+	ErrKeyTypeMismatch         = errors.New("key type does not match signing method")
+	ErrActiveKeyRemoval        = errors.New("cannot remove active signing key")
+	ErrTokenStoreNotConfigured = errors.New("token store is not configured")
+	ErrTokenStoreUnavailable   = errors.New("token store unavailable")
+
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+	ErrTokenTypeMismatch = errors.New("token type does not match")
+	ErrReservedClaim     = errors.New("claim name is reserved")
+)
+
+// Which of the pair a token is, selects its TTL and signing key
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
 )
 
+// Keeps track of revoked token IDs (jti)
+type TokenStore interface {
+	// Marks token as revoked until its expiration time
+	Revoke(jti string, exp time.Time) error
+	IsRevoked(jti string) (bool, error)
+	// Revokes jti in a single atomic step, reporting false when it was
+	// already revoked. Used to consume refresh tokens exactly once
+	RevokeIfNotRevoked(jti string, exp time.Time) (bool, error)
+}
+
+// Optionally implemented by a TokenStore that does network calls (e.g. Redis),
+// so ValidateTokenContext can cancel them. Other stores are abandoned on timeout
+type ContextTokenStore interface {
+	IsRevokedContext(ctx context.Context, jti string) (bool, error)
+}
+
+// Server-side record of an issued refresh token
+type RefreshTokenRecord struct {
+	JTI       string
+	UserID    uuidv7.UUID
+	ExpiresAt time.Time
+}
+
+// Persists issued refresh tokens so they can be invalidated (logout, admin action).
+// Get returns ErrRefreshTokenNotFound for unknown or deleted tokens
+type RefreshTokenRepository interface {
+	Save(record RefreshTokenRecord) error
+	Get(jti string) (*RefreshTokenRecord, error)
+	DeleteByUser(userID uuidv7.UUID) error
+	DeleteByJTI(jti string) error
+}
+
 type Claims struct {
 	UserID uuidv7.UUID `json:"user_id"`
 	Email  string      `json:"email"`
+	Roles  []string    `json:"roles,omitempty"`
+	Scopes []string    `json:"scopes,omitempty"`
+	// Empty in tokens issued before token types were introduced
+	TokenType TokenType `json:"token_type,omitempty"`
+	// Module-specific claims (tenant ID, feature flags), stored at the top
+	// level of the payload next to the standard ones
+	Extra map[string]any `json:"-"`
 	jwt.RegisteredClaims
 }
 
@@ -26,31 +100,252 @@ type TokenPair struct {
 }
 
 type JWTManager struct {
-	secretKey       string
+	key             signingKey
+	keysMu          sync.RWMutex
+	keys            map[string]signingKey // Keys identified by kid, the constructor key under ""
+	activeKID       string
+	refreshKey      *signingKey // Separate key for refresh tokens, nil uses the access keys
 	accessTokenTTL  time.Duration
 	refreshTokenTTL time.Duration
+	tokenStore      TokenStore
+	refreshRepo     RefreshTokenRepository
+	issuer          string
+	audience        []string
+	leeway          time.Duration
+}
+
+type Option func(*JWTManager)
+
+// Persists refresh tokens, only refresh tokens present in the repository are accepted
+func WithRefreshTokenRepository(repo RefreshTokenRepository) Option {
+	return func(m *JWTManager) {
+		m.refreshRepo = repo
+	}
+}
+
+// Enables revocation checks in ValidateToken
+func WithTokenStore(store TokenStore) Option {
+	return func(m *JWTManager) {
+		m.tokenStore = store
+	}
 }
 
-func NewJWTManager(secretKey string, accessTTL, refreshTTL time.Duration) *JWTManager {
-	return &JWTManager{
-		secretKey:       secretKey,
+// Sets iss claim on generated tokens and requires it on validation
+func WithIssuer(issuer string) Option {
+	return func(m *JWTManager) {
+		m.issuer = issuer
+	}
+}
+
+// Sets aud claim on generated tokens. On validation the token must
+// contain at least one of the given audiences
+func WithAudience(audience ...string) Option {
+	return func(m *JWTManager) {
+		m.audience = audience
+	}
+}
+
+// Signs and verifies refresh tokens with their own HS256 secret, so a leaked
+// access key can't mint refresh tokens and tokens can't stand in for each other
+func WithRefreshSecret(secret string) Option {
+	return func(m *JWTManager) {
+		m.refreshKey = &signingKey{
+			method:    jwt.SigningMethodHS256,
+			signKey:   []byte(secret),
+			verifyKey: []byte(secret),
+		}
+	}
+}
+
+// Tolerates clock skew between nodes when checking exp and nbf
+func WithLeeway(leeway time.Duration) Option {
+	return func(m *JWTManager) {
+		m.leeway = leeway
+	}
+}
+
+// Signing method with the keys used to sign and verify tokens
+type signingKey struct {
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+}
+
+// Creates manager that signs tokens with HS256 using a shared secret
+func NewJWTManager(secretKey string, accessTTL, refreshTTL time.Duration, opts ...Option) *JWTManager {
+	return newManager(signingKey{
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secretKey),
+		verifyKey: []byte(secretKey),
+	}, accessTTL, refreshTTL, opts)
+}
+
+// Creates manager that signs tokens with RS256.
+// privateKey may be nil for services that only verify tokens
+func NewRSAManager(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey, accessTTL, refreshTTL time.Duration, opts ...Option) *JWTManager {
+	var signKey any
+	if privateKey != nil {
+		signKey = privateKey
+		if publicKey == nil {
+			publicKey = &privateKey.PublicKey
+		}
+	}
+
+	return newManager(signingKey{
+		method:    jwt.SigningMethodRS256,
+		signKey:   signKey,
+		verifyKey: publicKey,
+	}, accessTTL, refreshTTL, opts)
+}
+
+func newManager(key signingKey, accessTTL, refreshTTL time.Duration, opts []Option) *JWTManager {
+	m := &JWTManager{
+		key:             key,
+		keys:            map[string]signingKey{"": key},
 		accessTokenTTL:  accessTTL,
 		refreshTokenTTL: refreshTTL,
 	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Registers an additional HMAC secret identified by kid.
+// Tokens carrying this kid are verified with it
+func (m *JWTManager) AddKey(kid, secret string) error {
+	if _, ok := m.key.method.(*jwt.SigningMethodHMAC); !ok {
+		return ErrKeyTypeMismatch
+	}
+	if kid == "" {
+		return ErrUnknownKeyID
+	}
+
+	m.keysMu.Lock()
+	defer m.keysMu.Unlock()
+
+	m.keys[kid] = signingKey{
+		method:    m.key.method,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+
+	return nil
+}
+
+// Makes the key with given kid the one used to sign new tokens
+func (m *JWTManager) SetActiveKey(kid string) error {
+	m.keysMu.Lock()
+	defer m.keysMu.Unlock()
+
+	if _, ok := m.keys[kid]; !ok {
+		return ErrUnknownKeyID
+	}
+
+	m.activeKID = kid
+	return nil
+}
+
+// Removes a retired key, tokens signed with it will no longer validate.
+// An empty kid retires the constructor key, which verifies tokens without kid
+func (m *JWTManager) RemoveKey(kid string) error {
+	m.keysMu.Lock()
+	defer m.keysMu.Unlock()
+
+	if kid == m.activeKID {
+		return ErrActiveKeyRemoval
+	}
+
+	delete(m.keys, kid)
+	return nil
+}
+
+// Returns key used for signing new tokens and its kid (empty for constructor key)
+func (m *JWTManager) activeKey(tokenType TokenType) (signingKey, string) {
+	if tokenType == TokenTypeRefresh && m.refreshKey != nil {
+		return *m.refreshKey, ""
+	}
+
+	m.keysMu.RLock()
+	defer m.keysMu.RUnlock()
+
+	return m.keys[m.activeKID], m.activeKID
+}
+
+// Tokens without kid are verified with the constructor key until it is removed
+func (m *JWTManager) verifyingKey(tokenType TokenType, kid string) (signingKey, error) {
+	if tokenType == TokenTypeRefresh && m.refreshKey != nil {
+		return *m.refreshKey, nil
+	}
+
+	m.keysMu.RLock()
+	defer m.keysMu.RUnlock()
+
+	key, ok := m.keys[kid]
+	if !ok {
+		return signingKey{}, ErrUnknownKeyID
+	}
+	return key, nil
 }
 
 // Generates access and refresh tokens
 func (m *JWTManager) GenerateTokenPair(userID uuidv7.UUID, email string) (*TokenPair, error) {
-	accessToken, expiresAt, err := m.generateToken(userID, email, m.accessTokenTTL)
+	return m.GenerateTokenPairWithClaims(userID, email, []string{}, []string{})
+}
+
+// Generates access and refresh tokens carrying user roles and scopes
+func (m *JWTManager) GenerateTokenPairWithClaims(userID uuidv7.UUID, email string, roles, scopes []string) (*TokenPair, error) {
+	return m.generateTokenPair(Claims{
+		UserID: userID,
+		Email:  email,
+		Roles:  roles,
+		Scopes: scopes,
+	})
+}
+
+// Generates access and refresh tokens carrying additional claims, returned in
+// Claims.Extra on validation. Registered and built-in claim names are rejected
+func (m *JWTManager) GenerateTokenPairWithExtra(userID uuidv7.UUID, email string, extra map[string]any) (*TokenPair, error) {
+	for name := range extra {
+		if reservedClaims[name] {
+			return nil, fmt.Errorf("%w: %s", ErrReservedClaim, name)
+		}
+	}
+
+	return m.generateTokenPair(Claims{
+		UserID: userID,
+		Email:  email,
+		Roles:  []string{},
+		Scopes: []string{},
+		Extra:  extra,
+	})
+}
+
+func (m *JWTManager) generateTokenPair(base Claims) (*TokenPair, error) {
+	accessToken, expiresAt, err := m.generateToken(base, TokenTypeAccess)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, _, err := m.generateToken(userID, email, m.refreshTokenTTL)
+	refreshJTI := uuidv7.New().String()
+	refreshToken, refreshExpiresAt, err := m.signToken(base, TokenTypeRefresh, refreshJTI)
 	if err != nil {
 		return nil, err
 	}
 
+	if m.refreshRepo != nil {
+		err := m.refreshRepo.Save(RefreshTokenRecord{
+			JTI:       refreshJTI,
+			UserID:    base.UserID,
+			ExpiresAt: refreshExpiresAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to save refresh token: %w", err)
+		}
+	}
+
 	return &TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -59,24 +354,53 @@ func (m *JWTManager) GenerateTokenPair(userID uuidv7.UUID, email string) (*Token
 }
 
 func (m *JWTManager) GenerateAccessToken(userID uuidv7.UUID, email string) (string, time.Time, error) {
-	return m.generateToken(userID, email, m.accessTokenTTL)
+	return m.generateToken(Claims{UserID: userID, Email: email}, TokenTypeAccess)
+}
+
+// Signs a token with user fields from base and fresh registered claims
+func (m *JWTManager) generateToken(base Claims, tokenType TokenType) (string, time.Time, error) {
+	return m.signToken(base, tokenType, uuidv7.New().String())
 }
 
-func (m *JWTManager) generateToken(userID uuidv7.UUID, email string, ttl time.Duration) (string, time.Time, error) {
-	expiresAt := time.Now().Add(ttl)
+func (m *JWTManager) ttlFor(tokenType TokenType) time.Duration {
+	if tokenType == TokenTypeRefresh {
+		return m.refreshTokenTTL
+	}
+	return m.accessTokenTTL
+}
+
+func (m *JWTManager) signToken(base Claims, tokenType TokenType, jti string) (string, time.Time, error) {
+	key, kid := m.activeKey(tokenType)
+	if key.signKey == nil {
+		return "", time.Time{}, ErrMissingSigningKey
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(m.ttlFor(tokenType))
 
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    base.UserID,
+		Email:     base.Email,
+		Roles:     base.Roles,
+		Scopes:    base.Scopes,
+		TokenType: tokenType,
+		Extra:     base.Extra,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Audience:  m.audience,
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ID:        jti,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(m.secretKey))
+	token := jwt.NewWithClaims(key.method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	tokenString, err := token.SignedString(key.signKey)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -84,19 +408,67 @@ func (m *JWTManager) generateToken(userID uuidv7.UUID, email string, ttl time.Du
 	return tokenString, expiresAt, nil
 }
 
-func (m *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
+// Validates a token of the given type, a token of the other type is rejected
+func (m *JWTManager) ValidateToken(tokenString string, tokenType TokenType) (*Claims, error) {
+	return m.ValidateTokenContext(context.Background(), tokenString, tokenType)
+}
+
+// Like ValidateToken, but gives up on the revocation check when ctx is done,
+// returning ErrTokenStoreUnavailable
+func (m *JWTManager) ValidateTokenContext(ctx context.Context, tokenString string, tokenType TokenType) (*Claims, error) {
+	claims, err := m.parseToken(tokenString, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.checkRevokedContext(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// Accepts either token type, for operations that work on both (revocation, introspection)
+func (m *JWTManager) validateAnyToken(tokenString string) (*Claims, error) {
+	claims, err := m.ValidateToken(tokenString, TokenTypeAccess)
+
+	// With a separate refresh key the access key can't even verify refresh tokens
+	wrongKey := m.refreshKey != nil &&
+		(errors.Is(err, jwt.ErrTokenSignatureInvalid) || errors.Is(err, jwt.ErrTokenUnverifiable))
+	if errors.Is(err, ErrTokenTypeMismatch) || wrongKey {
+		return m.ValidateToken(tokenString, TokenTypeRefresh)
+	}
+
+	return claims, err
+}
+
+// Verifies signature and expiration without consulting the token store
+func (m *JWTManager) parseToken(tokenString string, tokenType TokenType) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(
 		tokenString,
 		&Claims{},
 		func(token *jwt.Token) (any, error) {
-			// Verify signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			kid, _ := token.Header["kid"].(string)
+			key, err := m.verifyingKey(tokenType, kid)
+			if err != nil {
+				return nil, err
+			}
+
+			// Verify signing method matches the configured one (prevents algorithm confusion)
+			if token.Method.Alg() != key.method.Alg() {
 				return nil, ErrInvalidToken
 			}
-			return []byte(m.secretKey), nil
+			return key.verifyKey, nil
 		},
+		jwt.WithLeeway(m.leeway),
 	)
 
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrExpiredToken
+	}
+	if errors.Is(err, jwt.ErrTokenNotValidYet) {
+		return nil, ErrTokenNotYetValid
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -106,22 +478,248 @@ func (m *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 		return nil, ErrInvalidToken
 	}
 
-	// Check expiration
-	if claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
+	now := time.Now()
+
+	// Check expiration and not-before, tolerating clock skew
+	if claims.ExpiresAt != nil && claims.ExpiresAt.Add(m.leeway).Before(now) {
 		return nil, ErrExpiredToken
 	}
+	if claims.NotBefore != nil && claims.NotBefore.After(now.Add(m.leeway)) {
+		return nil, ErrTokenNotYetValid
+	}
+
+	if m.issuer != "" && claims.Issuer != m.issuer {
+		return nil, ErrInvalidIssuer
+	}
+
+	if len(m.audience) > 0 && !m.acceptsAudience(claims.Audience) {
+		return nil, ErrInvalidAudience
+	}
+
+	if claims.TokenType != "" && claims.TokenType != tokenType {
+		return nil, ErrTokenTypeMismatch
+	}
 
 	return claims, nil
 }
 
+func (m *JWTManager) acceptsAudience(audience jwt.ClaimStrings) bool {
+	for _, aud := range audience {
+		if slices.Contains(m.audience, aud) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *JWTManager) checkRevokedContext(ctx context.Context, claims *Claims) error {
+	if m.tokenStore == nil || claims.ID == "" {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrTokenStoreUnavailable, err)
+	}
+
+	if store, ok := m.tokenStore.(ContextTokenStore); ok {
+		revoked, err := store.IsRevokedContext(ctx, claims.ID)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrTokenStoreUnavailable, err)
+		}
+		if revoked {
+			return ErrRevokedToken
+		}
+		return nil
+	}
+
+	// Stores without context support keep running in the background after a timeout
+	done := make(chan error, 1)
+	go func() {
+		done <- m.checkRevoked(claims)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrTokenStoreUnavailable, ctx.Err())
+	}
+}
+
+func (m *JWTManager) checkRevoked(claims *Claims) error {
+	if m.tokenStore == nil || claims.ID == "" {
+		return nil
+	}
+
+	revoked, err := m.tokenStore.IsRevoked(claims.ID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTokenStoreUnavailable, err)
+	}
+	if revoked {
+		return ErrRevokedToken
+	}
+
+	return nil
+}
+
+// Revokes token so it's rejected by ValidateToken until it expires
+func (m *JWTManager) Revoke(tokenString string) error {
+	if m.tokenStore == nil {
+		return ErrTokenStoreNotConfigured
+	}
+
+	claims, err := m.validateAnyToken(tokenString)
+	if errors.Is(err, ErrRevokedToken) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return ErrInvalidToken
+	}
+
+	return m.tokenStore.Revoke(claims.ID, claims.ExpiresAt.Time)
+}
+
 // Creates new access token from refresh token
 func (m *JWTManager) RefreshAccessToken(refreshToken string) (string, time.Time, error) {
-	claims, err := m.ValidateToken(refreshToken)
+	claims, err := m.ValidateToken(refreshToken, TokenTypeRefresh)
 	if err != nil {
 		return "", time.Time{}, err
 	}
 
-	return m.GenerateAccessToken(claims.UserID, claims.Email)
+	if err := m.checkRefreshStored(claims); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return m.generateToken(*claims, TokenTypeAccess)
+}
+
+// Rejects refresh tokens deleted from the repository (logout, admin revocation)
+func (m *JWTManager) checkRefreshStored(claims *Claims) error {
+	if m.refreshRepo == nil {
+		return nil
+	}
+
+	_, err := m.refreshRepo.Get(claims.ID)
+	if errors.Is(err, ErrRefreshTokenNotFound) {
+		return ErrRevokedToken
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTokenStoreUnavailable, err)
+	}
+
+	return nil
+}
+
+// Invalidates all refresh tokens of the user, e.g. logout from all devices
+func (m *JWTManager) RevokeUserRefreshTokens(userID uuidv7.UUID) error {
+	if m.refreshRepo == nil {
+		return ErrTokenStoreNotConfigured
+	}
+
+	return m.refreshRepo.DeleteByUser(userID)
+}
+
+// Invalidates a single refresh token, e.g. logout from the current device
+func (m *JWTManager) RevokeRefreshToken(refreshToken string) error {
+	if m.refreshRepo == nil {
+		return ErrTokenStoreNotConfigured
+	}
+
+	claims, err := m.parseToken(refreshToken, TokenTypeRefresh)
+	if err != nil {
+		return err
+	}
+
+	return m.refreshRepo.DeleteByJTI(claims.ID)
+}
+
+// Rotates refresh token: issues a new pair and marks the old refresh token as used.
+// Presenting an already used refresh token again is treated as a possible token theft.
+// Of concurrent requests with the same token only one gets a new pair
+func (m *JWTManager) RefreshTokenPair(refreshToken string) (*TokenPair, error) {
+	if m.tokenStore == nil {
+		return nil, ErrTokenStoreNotConfigured
+	}
+
+	claims, err := m.parseToken(refreshToken, TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := m.checkRefreshStored(claims); err != nil {
+		return nil, err
+	}
+
+	// Consume old token before issuing a new one
+	consumed, err := m.tokenStore.RevokeIfNotRevoked(claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenStoreUnavailable, err)
+	}
+	if !consumed {
+		logger.Warn("Refresh token reuse detected",
+			slog.String("jti", claims.ID),
+			slog.String("user_id", claims.UserID.String()),
+		)
+		return nil, ErrRefreshTokenReused
+	}
+
+	if m.refreshRepo != nil {
+		if err := m.refreshRepo.DeleteByJTI(claims.ID); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrTokenStoreUnavailable, err)
+		}
+	}
+
+	return m.generateTokenPair(*claims)
+}
+
+// Token introspection response (RFC 7662). Only Active is set for inactive tokens
+type IntrospectionResult struct {
+	Active    bool     `json:"active"`
+	Subject   string   `json:"sub,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Scope     string   `json:"scope,omitempty"` // Space-separated
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	JTI       string   `json:"jti,omitempty"`
+}
+
+// Reports whether token is currently active. Invalid, expired or revoked tokens
+// are inactive without an error; an error means the check couldn't be performed
+func (m *JWTManager) Introspect(tokenString string) (IntrospectionResult, error) {
+	claims, err := m.validateAnyToken(tokenString)
+	if errors.Is(err, ErrTokenStoreUnavailable) {
+		return IntrospectionResult{}, err
+	}
+	if err != nil {
+		return IntrospectionResult{Active: false}, nil
+	}
+
+	result := IntrospectionResult{
+		Active:   true,
+		Subject:  claims.UserID.String(),
+		Email:    claims.Email,
+		Scope:    strings.Join(claims.Scopes, " "),
+		Issuer:   claims.Issuer,
+		Audience: claims.Audience,
+		JTI:      claims.ID,
+	}
+	if claims.IssuedAt != nil {
+		result.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.ExpiresAt != nil {
+		result.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+
+	return result, nil
 }
 
 func (m *JWTManager) GetRefreshTokenTTL() time.Duration {
@@ -131,3 +729,16 @@ func (m *JWTManager) GetRefreshTokenTTL() time.Duration {
 func (m *JWTManager) GetAccessTokenTTL() time.Duration {
 	return m.accessTokenTTL
 }
+
+// Decodes token claims WITHOUT verifying signature, expiration or revocation.
+//
+// WARNING: the result is attacker-controlled and must never be used for
+// authentication or authorization decisions. Only use it for non-security
+// purposes like routing or logging, before the token is fully validated
+func UnsafeParseClaims(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,457 @@
+package jwt
+
+import (
+	"errors"
+	"nexus/pkg/uuidv7"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+func newTestManager(opts ...Option) *JWTManager {
+	return NewJWTManager(testSecret, 15*time.Minute, 24*time.Hour, opts...)
+}
+
+func TestRefreshTokenPairRotates(t *testing.T) {
+	m := newTestManager(WithTokenStore(NewMemoryTokenStore()))
+	userID := uuidv7.New()
+
+	pair, err := m.GenerateTokenPair(userID, "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+
+	rotated, err := m.RefreshTokenPair(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshTokenPair: %v", err)
+	}
+	if rotated.RefreshToken == pair.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+
+	claims, err := m.ValidateToken(rotated.AccessToken, TokenTypeAccess)
+	if err != nil {
+		t.Fatalf("ValidateToken(new access token): %v", err)
+	}
+	if claims.UserID != userID {
+		t.Errorf("user_id = %s, want %s", claims.UserID, userID)
+	}
+
+	if _, err := m.RefreshTokenPair(rotated.RefreshToken); err != nil {
+		t.Errorf("RefreshTokenPair(rotated token): %v", err)
+	}
+}
+
+func TestRefreshTokenPairRejectsReuse(t *testing.T) {
+	m := newTestManager(WithTokenStore(NewMemoryTokenStore()))
+
+	pair, err := m.GenerateTokenPair(uuidv7.New(), "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+
+	if _, err := m.RefreshTokenPair(pair.RefreshToken); err != nil {
+		t.Fatalf("RefreshTokenPair: %v", err)
+	}
+
+	_, err = m.RefreshTokenPair(pair.RefreshToken)
+	if !errors.Is(err, ErrRefreshTokenReused) {
+		t.Errorf("reusing refresh token: err = %v, want %v", err, ErrRefreshTokenReused)
+	}
+}
+
+func TestRefreshTokenPairConcurrentReuse(t *testing.T) {
+	m := newTestManager(
+		WithTokenStore(NewMemoryTokenStore()),
+		WithRefreshTokenRepository(NewMemoryRefreshTokenRepository()),
+	)
+
+	pair, err := m.GenerateTokenPair(uuidv7.New(), "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+
+	const requests = 16
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		reused    int
+	)
+
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := m.RefreshTokenPair(pair.RefreshToken)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, ErrRefreshTokenReused), errors.Is(err, ErrRevokedToken):
+				reused++
+			default:
+				t.Errorf("RefreshTokenPair: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 1 || reused != requests-1 {
+		t.Errorf("succeeded = %d, rejected = %d, want 1 and %d", succeeded, reused, requests-1)
+	}
+}
+
+func TestRefreshTokenPairRequiresTokenStore(t *testing.T) {
+	m := newTestManager()
+
+	pair, err := m.GenerateTokenPair(uuidv7.New(), "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+
+	if _, err := m.RefreshTokenPair(pair.RefreshToken); !errors.Is(err, ErrTokenStoreNotConfigured) {
+		t.Errorf("err = %v, want %v", err, ErrTokenStoreNotConfigured)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	m := newTestManager()
+	userID := uuidv7.New()
+
+	legacy, _, err := m.GenerateAccessToken(userID, "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	if err := m.AddKey("2024-01", "rotated-secret"); err != nil {
+		t.Fatalf("AddKey: %v", err)
+	}
+	if err := m.SetActiveKey("2024-01"); err != nil {
+		t.Fatalf("SetActiveKey: %v", err)
+	}
+
+	rotated, _, err := m.GenerateAccessToken(userID, "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	parsed, err := jwt.NewParser().Parse(rotated, func(*jwt.Token) (any, error) {
+		return []byte("rotated-secret"), nil
+	})
+	if err != nil {
+		t.Fatalf("token not signed with the active key: %v", err)
+	}
+	if kid := parsed.Header["kid"]; kid != "2024-01" {
+		t.Errorf("kid header = %v, want 2024-01", kid)
+	}
+
+	// Both keys verify during the grace window
+	for name, token := range map[string]string{"legacy": legacy, "rotated": rotated} {
+		if _, err := m.ValidateToken(token, TokenTypeAccess); err != nil {
+			t.Errorf("ValidateToken(%s): %v", name, err)
+		}
+	}
+}
+
+func TestKeyRetirement(t *testing.T) {
+	m := newTestManager()
+	userID := uuidv7.New()
+
+	legacy, _, err := m.GenerateAccessToken(userID, "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	for _, kid := range []string{"2024-01", "2024-02"} {
+		if err := m.AddKey(kid, "secret-"+kid); err != nil {
+			t.Fatalf("AddKey(%s): %v", kid, err)
+		}
+	}
+	if err := m.SetActiveKey("2024-01"); err != nil {
+		t.Fatalf("SetActiveKey: %v", err)
+	}
+	old, _, err := m.GenerateAccessToken(userID, "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	if err := m.SetActiveKey("2024-02"); err != nil {
+		t.Fatalf("SetActiveKey: %v", err)
+	}
+
+	if err := m.RemoveKey("2024-02"); !errors.Is(err, ErrActiveKeyRemoval) {
+		t.Errorf("RemoveKey(active) err = %v, want %v", err, ErrActiveKeyRemoval)
+	}
+
+	if err := m.RemoveKey("2024-01"); err != nil {
+		t.Fatalf("RemoveKey: %v", err)
+	}
+	if _, err := m.ValidateToken(old, TokenTypeAccess); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("token of retired key: err = %v, want %v", err, ErrUnknownKeyID)
+	}
+
+	// The constructor key signs tokens without kid and is retired the same way
+	if _, err := m.ValidateToken(legacy, TokenTypeAccess); err != nil {
+		t.Fatalf("token of constructor key before retirement: %v", err)
+	}
+	if err := m.RemoveKey(""); err != nil {
+		t.Fatalf("RemoveKey(constructor key): %v", err)
+	}
+	if _, err := m.ValidateToken(legacy, TokenTypeAccess); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("token of retired constructor key: err = %v, want %v", err, ErrUnknownKeyID)
+	}
+}
+
+func TestUnknownKeyIDRejected(t *testing.T) {
+	m := newTestManager()
+	if err := m.AddKey("2024-01", "rotated-secret"); err != nil {
+		t.Fatalf("AddKey: %v", err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: uuidv7.New()})
+	token.Header["kid"] = "unknown"
+	signed, err := token.SignedString([]byte("rotated-secret"))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	if _, err := m.ValidateToken(signed, TokenTypeAccess); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("err = %v, want %v", err, ErrUnknownKeyID)
+	}
+}
+
+func TestIssuerAndAudienceValidation(t *testing.T) {
+	validator := newTestManager(WithIssuer("nexus"), WithAudience("nexus-api", "nexus-admin"))
+
+	tests := []struct {
+		name   string
+		issuer *JWTManager
+		want   error
+	}{
+		{"matching", newTestManager(WithIssuer("nexus"), WithAudience("nexus-admin")), nil},
+		{"other issuer", newTestManager(WithIssuer("evil"), WithAudience("nexus-api")), ErrInvalidIssuer},
+		{"missing issuer", newTestManager(WithAudience("nexus-api")), ErrInvalidIssuer},
+		{"other audience", newTestManager(WithIssuer("nexus"), WithAudience("billing")), ErrInvalidAudience},
+		{"missing audience", newTestManager(WithIssuer("nexus")), ErrInvalidAudience},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, _, err := tt.issuer.GenerateAccessToken(uuidv7.New(), "user@example.com")
+			if err != nil {
+				t.Fatalf("GenerateAccessToken: %v", err)
+			}
+
+			if _, err := validator.ValidateToken(token, TokenTypeAccess); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// Signs claims with the test secret, bypassing the manager's own defaults
+func signClaims(t *testing.T, claims Claims) string {
+	t.Helper()
+	return signClaimsWith(t, testSecret, claims)
+}
+
+func signClaimsWith(t *testing.T, secret string, claims Claims) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func TestLeewayToleratesClockSkew(t *testing.T) {
+	m := newTestManager(WithLeeway(30 * time.Second))
+
+	tests := []struct {
+		name    string
+		expired time.Duration
+		want    error
+	}{
+		{"within leeway", 10 * time.Second, nil},
+		{"past leeway", 60 * time.Second, ErrExpiredToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			token := signClaims(t, Claims{
+				UserID:    uuidv7.New(),
+				TokenType: TokenTypeAccess,
+				RegisteredClaims: jwt.RegisteredClaims{
+					IssuedAt:  jwt.NewNumericDate(now.Add(-time.Hour)),
+					ExpiresAt: jwt.NewNumericDate(now.Add(-tt.expired)),
+				},
+			})
+
+			if _, err := m.ValidateToken(token, TokenTypeAccess); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeewayAppliesToNotBefore(t *testing.T) {
+	m := newTestManager(WithLeeway(30 * time.Second))
+	token := signClaims(t, Claims{
+		UserID:    uuidv7.New(),
+		TokenType: TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			NotBefore: jwt.NewNumericDate(time.Now().Add(10 * time.Second)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+
+	if _, err := m.ValidateToken(token, TokenTypeAccess); err != nil {
+		t.Errorf("token from a clock 10s ahead: %v", err)
+	}
+}
+
+func TestRefreshAccessTokenRequiresStoredToken(t *testing.T) {
+	repo := NewMemoryRefreshTokenRepository()
+	m := newTestManager(WithRefreshTokenRepository(repo))
+	userID := uuidv7.New()
+
+	pair, err := m.GenerateTokenPair(userID, "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+	if _, _, err := m.RefreshAccessToken(pair.RefreshToken); err != nil {
+		t.Fatalf("RefreshAccessToken(stored token): %v", err)
+	}
+
+	// Signed with the right key but never recorded
+	unstored, err := newTestManager().GenerateTokenPair(userID, "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+	if _, _, err := m.RefreshAccessToken(unstored.RefreshToken); !errors.Is(err, ErrRevokedToken) {
+		t.Errorf("unstored token: err = %v, want %v", err, ErrRevokedToken)
+	}
+}
+
+func TestRevokeRefreshTokens(t *testing.T) {
+	m := newTestManager(WithRefreshTokenRepository(NewMemoryRefreshTokenRepository()))
+	userID := uuidv7.New()
+
+	laptop, err := m.GenerateTokenPair(userID, "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+	phone, err := m.GenerateTokenPair(userID, "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+
+	// Logout from the laptop only
+	if err := m.RevokeRefreshToken(laptop.RefreshToken); err != nil {
+		t.Fatalf("RevokeRefreshToken: %v", err)
+	}
+	if _, _, err := m.RefreshAccessToken(laptop.RefreshToken); !errors.Is(err, ErrRevokedToken) {
+		t.Errorf("logged out token: err = %v, want %v", err, ErrRevokedToken)
+	}
+	if _, _, err := m.RefreshAccessToken(phone.RefreshToken); err != nil {
+		t.Fatalf("other device: %v", err)
+	}
+
+	// Logout everywhere
+	if err := m.RevokeUserRefreshTokens(userID); err != nil {
+		t.Fatalf("RevokeUserRefreshTokens: %v", err)
+	}
+	if _, _, err := m.RefreshAccessToken(phone.RefreshToken); !errors.Is(err, ErrRevokedToken) {
+		t.Errorf("after logout everywhere: err = %v, want %v", err, ErrRevokedToken)
+	}
+}
+
+func TestIntrospect(t *testing.T) {
+	m := newTestManager(WithIssuer("nexus"))
+	userID := uuidv7.New()
+
+	pair, err := m.GenerateTokenPairWithClaims(userID, "user@example.com", nil, []string{"orders:read", "orders:write"})
+	if err != nil {
+		t.Fatalf("GenerateTokenPairWithClaims: %v", err)
+	}
+
+	active, err := m.Introspect(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("Introspect(active): %v", err)
+	}
+	if !active.Active || active.Subject != userID.String() || active.Scope != "orders:read orders:write" ||
+		active.Issuer != "nexus" || active.JTI == "" || active.ExpiresAt == 0 {
+		t.Errorf("active token = %+v", active)
+	}
+
+	expired := signClaims(t, Claims{
+		UserID:    userID,
+		TokenType: TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "nexus",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+	})
+
+	for name, token := range map[string]string{"expired": expired, "malformed": "not.a.jwt"} {
+		result, err := m.Introspect(token)
+		if err != nil {
+			t.Errorf("Introspect(%s): %v", name, err)
+		}
+		if result.Active || result.Subject != "" {
+			t.Errorf("%s token = %+v, want only active=false", name, result)
+		}
+	}
+}
+
+func TestRefreshSecretSeparatesTokenTypes(t *testing.T) {
+	const refreshSecret = "refresh-secret"
+	m := newTestManager(WithRefreshSecret(refreshSecret))
+
+	pair, err := m.GenerateTokenPair(uuidv7.New(), "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+
+	if _, err := m.ValidateToken(pair.AccessToken, TokenTypeAccess); err != nil {
+		t.Errorf("access token as access: %v", err)
+	}
+	if _, err := m.ValidateToken(pair.RefreshToken, TokenTypeRefresh); err != nil {
+		t.Errorf("refresh token as refresh: %v", err)
+	}
+
+	// Claims without token_type, so only the key keeps the types apart
+	claims := Claims{
+		UserID: uuidv7.New(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		tokenType TokenType
+	}{
+		{"issued access token as refresh", pair.AccessToken, TokenTypeRefresh},
+		{"issued refresh token as access", pair.RefreshToken, TokenTypeAccess},
+		{"signed with access secret as refresh", signClaimsWith(t, testSecret, claims), TokenTypeRefresh},
+		{"signed with refresh secret as access", signClaimsWith(t, refreshSecret, claims), TokenTypeAccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := m.ValidateToken(tt.token, tt.tokenType); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+				t.Errorf("ValidateToken err = %v, want %v", err, jwt.ErrTokenSignatureInvalid)
+			}
+		})
+	}
+}
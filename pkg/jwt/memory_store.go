@@ -0,0 +1,136 @@
+package jwt
+
+import (
+	"nexus/pkg/uuidv7"
+	"sync"
+	"time"
+)
+
+// In-memory TokenStore, suitable for a single instance or tests.
+// Entries are evicted once the revoked token would have expired anyway
+type MemoryTokenStore struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		revoked: make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryTokenStore) Revoke(jti string, exp time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired(time.Now())
+	s.revoked[jti] = exp
+
+	return nil
+}
+
+func (s *MemoryTokenStore) RevokeIfNotRevoked(jti string, exp time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired(time.Now())
+
+	if _, ok := s.revoked[jti]; ok {
+		return false, nil
+	}
+	s.revoked[jti] = exp
+
+	return true, nil
+}
+
+func (s *MemoryTokenStore) IsRevoked(jti string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exp, ok := s.revoked[jti]
+	if !ok {
+		return false, nil
+	}
+
+	if exp.Before(time.Now()) {
+		delete(s.revoked, jti)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Must be called with mu held
+func (s *MemoryTokenStore) evictExpired(now time.Time) {
+	for jti, exp := range s.revoked {
+		if exp.Before(now) {
+			delete(s.revoked, jti)
+		}
+	}
+}
+
+// In-memory RefreshTokenRepository, suitable for a single instance or tests
+type MemoryRefreshTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]RefreshTokenRecord
+}
+
+func NewMemoryRefreshTokenRepository() *MemoryRefreshTokenRepository {
+	return &MemoryRefreshTokenRepository{
+		tokens: make(map[string]RefreshTokenRecord),
+	}
+}
+
+func (r *MemoryRefreshTokenRepository) Save(record RefreshTokenRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.evictExpired(time.Now())
+	r.tokens[record.JTI] = record
+
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) Get(jti string) (*RefreshTokenRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.tokens[jti]
+	if !ok || record.ExpiresAt.Before(time.Now()) {
+		delete(r.tokens, jti)
+		return nil, ErrRefreshTokenNotFound
+	}
+
+	return &record, nil
+}
+
+func (r *MemoryRefreshTokenRepository) DeleteByUser(userID uuidv7.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for jti, record := range r.tokens {
+		if record.UserID == userID {
+			delete(r.tokens, jti)
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) DeleteByJTI(jti string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.tokens, jti)
+
+	return nil
+}
+
+// Must be called with mu held
+func (r *MemoryRefreshTokenRepository) evictExpired(now time.Time) {
+	for jti, record := range r.tokens {
+		if record.ExpiresAt.Before(now) {
+			delete(r.tokens, jti)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package jwt
+
+import (
+	"errors"
+	"nexus/pkg/uuidv7"
+	"testing"
+	"time"
+)
+
+func TestMemoryRefreshTokenRepository(t *testing.T) {
+	repo := NewMemoryRefreshTokenRepository()
+	alice, bob := uuidv7.New(), uuidv7.New()
+	exp := time.Now().Add(time.Hour)
+
+	for _, record := range []RefreshTokenRecord{
+		{JTI: "alice-laptop", UserID: alice, ExpiresAt: exp},
+		{JTI: "alice-phone", UserID: alice, ExpiresAt: exp},
+		{JTI: "bob-laptop", UserID: bob, ExpiresAt: exp},
+		{JTI: "expired", UserID: bob, ExpiresAt: time.Now().Add(-time.Second)},
+	} {
+		if err := repo.Save(record); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	if record, err := repo.Get("alice-laptop"); err != nil || record.UserID != alice {
+		t.Errorf("Get(alice-laptop) = %+v, %v", record, err)
+	}
+	if _, err := repo.Get("expired"); !errors.Is(err, ErrRefreshTokenNotFound) {
+		t.Errorf("Get(expired) err = %v, want %v", err, ErrRefreshTokenNotFound)
+	}
+
+	repo.DeleteByJTI("alice-phone")
+	if _, err := repo.Get("alice-phone"); !errors.Is(err, ErrRefreshTokenNotFound) {
+		t.Errorf("Get after DeleteByJTI err = %v, want %v", err, ErrRefreshTokenNotFound)
+	}
+
+	repo.DeleteByUser(alice)
+	if _, err := repo.Get("alice-laptop"); !errors.Is(err, ErrRefreshTokenNotFound) {
+		t.Errorf("Get after DeleteByUser err = %v, want %v", err, ErrRefreshTokenNotFound)
+	}
+	if _, err := repo.Get("bob-laptop"); err != nil {
+		t.Errorf("other user's token deleted: %v", err)
+	}
+}
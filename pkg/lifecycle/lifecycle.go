@@ -0,0 +1,52 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Releases a component's resources, should give up when ctx is done
+type Hook func(ctx context.Context) error
+
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+// Collects shutdown hooks and runs them in reverse registration order,
+// so components are closed before the dependencies they were built on
+type Closer struct {
+	mu    sync.Mutex
+	hooks []namedHook
+}
+
+func New() *Closer {
+	return &Closer{}
+}
+
+func (c *Closer) Register(name string, hook Hook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hooks = append(c.hooks, namedHook{name: name, hook: hook})
+}
+
+// Runs every hook (LIFO) even if some fail, returning all errors joined.
+// Hooks are removed, so calling Close again is a no-op
+func (c *Closer) Close(ctx context.Context) error {
+	c.mu.Lock()
+	hooks := c.hooks
+	c.hooks = nil
+	c.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].hook(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s: %w", hooks[i].name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
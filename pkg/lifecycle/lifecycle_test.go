@@ -0,0 +1,73 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCloseRunsHooksInReverseOrder(t *testing.T) {
+	c := New()
+	var order []string
+	errFlush := errors.New("flush failed")
+
+	for _, name := range []string{"database", "cache", "http server"} {
+		c.Register(name, func(context.Context) error {
+			order = append(order, name)
+			if name == "cache" {
+				return errFlush
+			}
+			return nil
+		})
+	}
+
+	err := c.Close(context.Background())
+
+	want := []string{"http server", "cache", "database"}
+	if !slices.Equal(order, want) {
+		t.Errorf("order = %q, want %q", order, want)
+	}
+	if !errors.Is(err, errFlush) || !strings.Contains(err.Error(), "failed to close cache") {
+		t.Errorf("err = %v, want the cache error with its name", err)
+	}
+}
+
+func TestCloseJoinsAllErrors(t *testing.T) {
+	c := New()
+	errA, errB := errors.New("a"), errors.New("b")
+	c.Register("a", func(context.Context) error { return errA })
+	c.Register("b", func(context.Context) error { return errB })
+
+	err := c.Close(context.Background())
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("err = %v, want both errors", err)
+	}
+}
+
+func TestCloseTwiceIsNoop(t *testing.T) {
+	c := New()
+	var calls int
+	c.Register("database", func(context.Context) error { calls++; return nil })
+
+	c.Close(context.Background())
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("hook ran %d times, want 1", calls)
+	}
+}
+
+func TestClosePassesContext(t *testing.T) {
+	c := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c.Register("database", func(ctx context.Context) error { return ctx.Err() })
+
+	if err := c.Close(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+}
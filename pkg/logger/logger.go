@@ -5,12 +5,15 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type Logger struct {
 	*slog.Logger
+	output io.Writer
 }
 
 type Config struct {
@@ -18,7 +21,29 @@ type Config struct {
 	Format     string // json, text
 	Output     io.Writer
 	AddSource  bool // file/line
+	SourceFunc bool // With AddSource, also the calling function as package.Func
 	TimeFormat string
+	UTC        bool            // Converts timestamps to UTC before formatting
+	Syslog     *SyslogConfig   // When set, records go to syslog instead of Output
+	Sampling   *SamplingConfig // When set, repeated messages below error level are sampled
+	// Masks matches in messages and string attributes. Every record is scanned,
+	// so keep the list short
+	RedactPatterns []*regexp.Regexp
+	// Renames the built-in keys (time, level, msg, source) to match the log
+	// pipeline schema, e.g. {"level": "severity", "msg": "message"}
+	FieldNames     map[string]string
+	LowercaseLevel bool // "info" instead of "INFO"
+	// Guards log storage against huge attributes (e.g. request bodies from
+	// libraries): string values over MaxAttrLength bytes are cut, DropKeys are removed
+	MaxAttrLength int
+	DropKeys      []string
+}
+
+type SyslogConfig struct {
+	Network  string // "udp", "tcp", "unix" or empty for the local syslog daemon
+	Address  string
+	Facility string // user, daemon, local0..local7 (default user)
+	Tag      string
 }
 
 type ContextKey string
@@ -54,12 +79,29 @@ func New(cfg Config) *Logger {
 		level = slog.LevelInfo
 	}
 
+	dropKeys := make(map[string]bool, len(cfg.DropKeys))
+	for _, key := range cfg.DropKeys {
+		dropKeys[key] = true
+	}
+
 	opts := &slog.HandlerOptions{
 		Level:     level,
 		AddSource: cfg.AddSource,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			builtin := len(groups) == 0 && isBuiltinKey(a.Key)
+			if !builtin {
+				if dropKeys[a.Key] {
+					return slog.Attr{} // Empty attrs are skipped by the handler
+				}
+				if cfg.MaxAttrLength > 0 && a.Value.Kind() == slog.KindString {
+					a.Value = slog.StringValue(truncate(a.Value.String(), cfg.MaxAttrLength))
+				}
+			}
 			if a.Key == slog.TimeKey {
 				if t, ok := a.Value.Any().(time.Time); ok {
+					if cfg.UTC {
+						t = t.UTC()
+					}
 					a.Value = slog.StringValue(t.Format(cfg.TimeFormat))
 				}
 			}
@@ -68,24 +110,95 @@ func New(cfg Config) *Logger {
 					if idx := strings.Index(source.File, "nexus/"); idx != -1 {
 						source.File = source.File[idx:]
 					}
+					if cfg.SourceFunc {
+						// Text handler prints only file:line for *slog.Source
+						a.Value = slog.GroupValue(
+							slog.String("function", shortFunction(source.Function)),
+							slog.String("file", source.File),
+							slog.Int("line", source.Line),
+						)
+					}
+				}
+			}
+			if a.Key == slog.LevelKey && cfg.LowercaseLevel {
+				if level, ok := a.Value.Any().(slog.Level); ok {
+					a.Value = slog.StringValue(strings.ToLower(level.String()))
+				}
+			}
+			if builtin {
+				if name, ok := cfg.FieldNames[a.Key]; ok {
+					a.Key = name
 				}
 			}
 			return a
 		},
 	}
 
+	newHandler := func(w io.Writer) slog.Handler {
+		if cfg.Format == "json" {
+			return slog.NewJSONHandler(w, opts)
+		}
+		return slog.NewTextHandler(w, opts)
+	}
+
 	var handler slog.Handler
-	if cfg.Format == "json" {
-		handler = slog.NewJSONHandler(cfg.Output, opts)
+	if cfg.Syslog != nil {
+		syslogHandler, err := newSyslogHandler(*cfg.Syslog, newHandler)
+		if err != nil {
+			// Keep logging somewhere rather than failing startup
+			handler = newHandler(cfg.Output)
+			slog.New(handler).Warn("Failed to connect to syslog, falling back to output",
+				slog.Any("error", err))
+		} else {
+			handler = syslogHandler
+		}
 	} else {
-		handler = slog.NewTextHandler(cfg.Output, opts)
+		handler = newHandler(cfg.Output)
+	}
+
+	if len(cfg.RedactPatterns) > 0 {
+		handler = newRedactingHandler(handler, cfg.RedactPatterns)
+	}
+
+	if cfg.Sampling != nil {
+		handler = newSamplingHandler(handler, *cfg.Sampling)
 	}
 
 	return &Logger{
 		Logger: slog.New(handler),
+		output: cfg.Output,
 	}
 }
 
+// Cuts s to at most limit bytes plus an ellipsis, on a UTF-8 boundary
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
+
+func isBuiltinKey(key string) bool {
+	switch key {
+	case slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey:
+		return true
+	}
+	return false
+}
+
+// Trims the import path, e.g. "nexus/pkg/logger.(*Logger).Flush" -> "logger.(*Logger).Flush"
+func shortFunction(function string) string {
+	if idx := strings.LastIndex(function, "/"); idx != -1 {
+		return function[idx+1:]
+	}
+	return function
+}
+
 func Init(cfg Config) {
 	defaultLogger = New(cfg)
 	slog.SetDefault(defaultLogger.Logger)
@@ -102,6 +215,14 @@ func Default() *Logger {
 	return defaultLogger
 }
 
+// Flushes buffered output (e.g. a bufio.Writer), no-op for unbuffered writers
+func (l *Logger) Flush() error {
+	if f, ok := l.output.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
 func FromContext(ctx context.Context) *Logger {
 	logger := Default()
 
@@ -122,6 +243,7 @@ func FromContext(ctx context.Context) *Logger {
 	if len(attrs) > 0 {
 		return &Logger{
 			Logger: logger.With(attrs...),
+			output: logger.output,
 		}
 	}
 
@@ -146,6 +268,7 @@ func (l *Logger) WithContext(ctx context.Context) *Logger {
 	if len(attrs) > 0 {
 		return &Logger{
 			Logger: l.With(attrs...),
+			output: l.output,
 		}
 	}
 
@@ -159,12 +282,14 @@ func (l *Logger) WithFields(fields map[string]any) *Logger {
 	}
 	return &Logger{
 		Logger: l.With(attrs...),
+		output: l.output,
 	}
 }
 
 func (l *Logger) WithError(err error) *Logger {
 	return &Logger{
 		Logger: l.With(slog.String("error", err.Error())),
+		output: l.output,
 	}
 }
 
@@ -0,0 +1,233 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Runs with a local zone east of UTC so conversion is observable
+func withLocalZone(t *testing.T) {
+	t.Helper()
+
+	local := time.Local
+	time.Local = time.FixedZone("UTC+5", 5*60*60)
+	t.Cleanup(func() { time.Local = local })
+}
+
+func loggedTime(t *testing.T, cfg Config) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	cfg.Format = "json"
+	cfg.Output = &buf
+	New(cfg).Info("hello")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode record %q: %v", buf.String(), err)
+	}
+	ts, _ := record["time"].(string)
+	return ts
+}
+
+func TestTimestampUTC(t *testing.T) {
+	withLocalZone(t)
+
+	tests := []struct {
+		name       string
+		cfg        Config
+		layout     string
+		wantOffset int
+	}{
+		{"local by default", Config{}, time.RFC3339, 5 * 60 * 60},
+		{"utc", Config{UTC: true}, time.RFC3339, 0},
+		{"utc with custom format", Config{UTC: true, TimeFormat: "2006-01-02 15:04:05 -0700"}, "2006-01-02 15:04:05 -0700", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := loggedTime(t, tt.cfg)
+
+			parsed, err := time.Parse(tt.layout, ts)
+			if err != nil {
+				t.Fatalf("time %q not in format %q: %v", ts, tt.layout, err)
+			}
+			if _, offset := parsed.Zone(); offset != tt.wantOffset {
+				t.Errorf("time %q has offset %ds, want %ds", ts, offset, tt.wantOffset)
+			}
+		})
+	}
+}
+
+type sourceProbe struct{ l *Logger }
+
+func (p *sourceProbe) log() {
+	p.l.Info("from method")
+}
+
+func TestSourceFunction(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Format: "json", Output: &buf, AddSource: true, SourceFunc: true})
+
+	l.Info("from test")
+	(&sourceProbe{l: l}).log()
+
+	want := []string{"logger.TestSourceFunction", "logger.(*sourceProbe).log"}
+	for i, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var record struct {
+			Source map[string]any `json:"source"`
+		}
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("decode record %q: %v", line, err)
+		}
+
+		if got := record.Source["function"]; got != want[i] {
+			t.Errorf("record %d function = %v, want %v", i, got, want[i])
+		}
+		if file, _ := record.Source["file"].(string); !strings.HasSuffix(file, "logger_test.go") {
+			t.Errorf("record %d file = %q, want logger_test.go", i, file)
+		}
+	}
+}
+
+func TestShortFunction(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"nexus/internal/adapter/http/shared/middleware.(*AuthMiddleware).RequireAuth.func1", "middleware.(*AuthMiddleware).RequireAuth.func1"},
+		{"nexus/pkg/logger.Init", "logger.Init"},
+		{"main.main", "main.main"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := shortFunction(tt.in); got != tt.want {
+			t.Errorf("shortFunction(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFieldNamesAndLowercaseLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want map[string]any // Expected key -> value, nil value only checks presence
+		gone []string
+	}{
+		{
+			name: "default",
+			cfg:  Config{},
+			want: map[string]any{"level": "WARN", "msg": "disk almost full", "time": nil},
+		},
+		{
+			name: "renamed and lowercase",
+			cfg: Config{
+				FieldNames:     map[string]string{"level": "severity", "msg": "message", "time": "ts"},
+				LowercaseLevel: true,
+			},
+			want: map[string]any{"severity": "warn", "message": "disk almost full", "ts": nil},
+			gone: []string{"level", "msg", "time"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.cfg.Format = "json"
+			tt.cfg.Output = &buf
+			New(tt.cfg).Warn("disk almost full", slog.Group("disk", slog.String("level", "ssd")))
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("decode record %q: %v", buf.String(), err)
+			}
+
+			for key, value := range tt.want {
+				got, ok := record[key]
+				if !ok || (value != nil && got != value) {
+					t.Errorf("%s = %v, want %v", key, got, value)
+				}
+			}
+			for _, key := range tt.gone {
+				if _, ok := record[key]; ok {
+					t.Errorf("%s still present in %v", key, record)
+				}
+			}
+			// Only the built-in keys are renamed, not attributes in groups
+			if disk, _ := record["disk"].(map[string]any); disk["level"] != "ssd" {
+				t.Errorf("disk = %v, want level kept", record["disk"])
+			}
+		})
+	}
+}
+
+func TestMaxAttrLengthAndDropKeys(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Format: "json", Output: &buf, MaxAttrLength: 8, DropKeys: []string{"body"}})
+
+	l.Info("a message longer than the attribute limit",
+		"path", "/api/v1/orders",
+		"body", `{"huge":"payload"}`,
+		"status", 200,
+		slog.Group("req", slog.String("body", "nested"), slog.String("agent", "curl/8.0")))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode record %q: %v", buf.String(), err)
+	}
+
+	if record["path"] != "/api/v1/…" {
+		t.Errorf("path = %v, want it cut to 8 bytes with an ellipsis", record["path"])
+	}
+	if _, ok := record["body"]; ok {
+		t.Errorf("body = %v, want it dropped", record["body"])
+	}
+	if record["status"] != float64(200) {
+		t.Errorf("status = %v, want non-strings untouched", record["status"])
+	}
+	if record["msg"] != "a message longer than the attribute limit" {
+		t.Errorf("msg = %v, want the built-in message untouched", record["msg"])
+	}
+	req, _ := record["req"].(map[string]any)
+	if _, ok := req["body"]; ok || req["agent"] != "curl/8.0" {
+		t.Errorf("req = %v, want body dropped and agent kept", req)
+	}
+}
+
+func TestMaxAttrLengthOffByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	long := strings.Repeat("x", 10000)
+	New(Config{Format: "json", Output: &buf}).Info("request", "body", long)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode record: %v", err)
+	}
+	if record["body"] != long {
+		t.Error("body was changed without MaxAttrLength or DropKeys")
+	}
+}
+
+func TestTruncateKeepsRunesWhole(t *testing.T) {
+	tests := []struct {
+		in    string
+		limit int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly8", 8, "exactly8"},
+		{"héllo wörld", 2, "h…"}, // é is two bytes, cut before it
+		{"héllo wörld", 3, "hé…"},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.in, tt.limit); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.in, tt.limit, got, tt.want)
+		}
+	}
+}
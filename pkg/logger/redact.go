@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redactMask = "***"
+
+// Masks regexp matches in the message and string attribute values.
+// Patterns with capture groups only mask the groups, e.g. `token=(\S+)`
+// logs "token=***"; patterns without groups mask the whole match
+type redactingHandler struct {
+	inner    slog.Handler
+	patterns []*regexp.Regexp
+}
+
+func newRedactingHandler(inner slog.Handler, patterns []*regexp.Regexp) slog.Handler {
+	return &redactingHandler{inner: inner, patterns: patterns}
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, h.redact(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.inner.Handle(ctx, redacted)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(a)
+	}
+	return &redactingHandler{inner: h.inner.WithAttrs(redacted), patterns: h.patterns}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{inner: h.inner.WithGroup(name), patterns: h.patterns}
+}
+
+func (h *redactingHandler) redactAttr(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(h.redact(a.Value.String()))
+	case slog.KindGroup:
+		group := a.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			redacted[i] = h.redactAttr(ga)
+		}
+		a.Value = slog.GroupValue(redacted...)
+	}
+	return a
+}
+
+func (h *redactingHandler) redact(s string) string {
+	for _, p := range h.patterns {
+		s = maskMatches(p, s)
+	}
+	return s
+}
+
+func maskMatches(p *regexp.Regexp, s string) string {
+	if p.NumSubexp() == 0 {
+		return p.ReplaceAllString(s, redactMask)
+	}
+
+	matches := p.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		// Pairs after the first are the group bounds, -1 for groups that didn't match
+		for g := 2; g < len(m); g += 2 {
+			start, end := m[g], m[g+1]
+			if start < last || start == end {
+				continue // Unmatched, empty or nested in an already masked group
+			}
+			b.WriteString(s[last:start])
+			b.WriteString(redactMask)
+			last = end
+		}
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
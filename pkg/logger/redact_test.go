@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"testing"
+)
+
+var (
+	// Masks only the token, keeping the scheme readable
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+([A-Za-z0-9\-._~+/]+=*)`)
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+func redactedRecord(t *testing.T, cfg Config, log func(l *Logger)) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	cfg.Format = "json"
+	cfg.Output = &buf
+	log(New(cfg))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode record %q: %v", buf.String(), err)
+	}
+	return record
+}
+
+func TestRedactPatterns(t *testing.T) {
+	cfg := Config{RedactPatterns: []*regexp.Regexp{bearerPattern, emailPattern}}
+
+	record := redactedRecord(t, cfg, func(l *Logger) {
+		l.With("owner", "ops@example.com").Info("auth header Bearer eyJhbGciOi.eyJzdWIi.sig for alice@example.com",
+			"header", "Bearer abc123",
+			slog.Group("user", slog.String("email", "bob@example.org")),
+			"attempts", 3)
+	})
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"message", record["msg"], "auth header Bearer *** for ***"},
+		{"attribute", record["header"], "Bearer ***"},
+		{"with attribute", record["owner"], "***"},
+		{"group attribute", record["user"].(map[string]any)["email"], "***"},
+		{"non-string attribute", record["attempts"], float64(3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactDisabledByDefault(t *testing.T) {
+	record := redactedRecord(t, Config{}, func(l *Logger) {
+		l.Info("token Bearer abc123 for alice@example.com")
+	})
+
+	if want := "token Bearer abc123 for alice@example.com"; record["msg"] != want {
+		t.Errorf("msg = %v, want %v", record["msg"], want)
+	}
+}
+
+func TestMaskMatchesGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		in      string
+		want    string
+	}{
+		{"whole match without groups", `\d{4}`, "pin 1234 and 5678", "pin *** and ***"},
+		{"only the group", `token=(\w+)`, "token=abc&token=def", "token=***&token=***"},
+		{"optional group unmatched", `key(=\w+)?`, "key and key=v", "key and key***"},
+		{"no match", `secret`, "nothing here", "nothing here"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maskMatches(regexp.MustCompile(tt.pattern), tt.in); got != tt.want {
+				t.Errorf("maskMatches(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
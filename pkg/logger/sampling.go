@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Within each window, the first First records with the same level and message
+// are logged, then every Thereafter-th one (0 drops the rest).
+// Error records are never sampled
+type SamplingConfig struct {
+	Window     time.Duration
+	First      int
+	Thereafter int
+}
+
+type samplingKey struct {
+	level slog.Level
+	msg   string
+}
+
+// Counters shared by a handler and the handlers derived from it
+type sampler struct {
+	cfg         SamplingConfig
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[samplingKey]int
+}
+
+func (s *sampler) allow(level slog.Level, msg string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Resetting all counters at once keeps memory bounded by the messages of one window
+	now := time.Now()
+	if now.Sub(s.windowStart) >= s.cfg.Window {
+		s.windowStart = now
+		clear(s.counts)
+	}
+
+	key := samplingKey{level: level, msg: msg}
+	s.counts[key]++
+	n := s.counts[key]
+
+	if n <= s.cfg.First {
+		return true
+	}
+	return s.cfg.Thereafter > 0 && (n-s.cfg.First)%s.cfg.Thereafter == 0
+}
+
+type samplingHandler struct {
+	inner   slog.Handler
+	sampler *sampler
+}
+
+func newSamplingHandler(inner slog.Handler, cfg SamplingConfig) slog.Handler {
+	if cfg.Window <= 0 {
+		cfg.Window = time.Second
+	}
+
+	return &samplingHandler{
+		inner: inner,
+		sampler: &sampler{
+			cfg:    cfg,
+			counts: make(map[samplingKey]int),
+		},
+	}
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelError && !h.sampler.allow(r.Level, r.Message) {
+		return nil
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{inner: h.inner.WithAttrs(attrs), sampler: h.sampler}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{inner: h.inner.WithGroup(name), sampler: h.sampler}
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSamplingBoundsRepeatedMessages(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := SamplingConfig{Window: time.Minute, First: 10, Thereafter: 100}
+	l := New(Config{Level: "debug", Format: "json", Output: &buf, Sampling: &cfg})
+
+	for range 1000 {
+		l.Debug("cache miss")
+	}
+	l.Debug("other message")
+
+	got := bytes.Count(buf.Bytes(), []byte(`"msg":"cache miss"`))
+	// First 10, then every 100th of the remaining 990
+	if want := cfg.First + (1000-cfg.First)/cfg.Thereafter; got != want {
+		t.Errorf("sampled records = %d, want %d", got, want)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"msg":"other message"`)) {
+		t.Error("a different message was sampled away")
+	}
+}
+
+func TestSamplingKeepsErrors(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Format: "json", Output: &buf, Sampling: &SamplingConfig{Window: time.Minute, First: 1}})
+
+	for range 50 {
+		l.Error("payment failed")
+	}
+
+	if got := bytes.Count(buf.Bytes(), []byte("\n")); got != 50 {
+		t.Errorf("error records = %d, want all 50", got)
+	}
+}
+
+func TestSamplingResetsEachWindow(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Format: "json", Output: &buf, Sampling: &SamplingConfig{Window: 20 * time.Millisecond, First: 1}})
+
+	l.Info("tick")
+	l.Info("tick")
+	time.Sleep(30 * time.Millisecond)
+	l.Info("tick")
+
+	if got := bytes.Count(buf.Bytes(), []byte("\n")); got != 2 {
+		t.Errorf("records = %d, want the first of each window", got)
+	}
+}
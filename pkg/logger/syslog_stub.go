@@ -0,0 +1,13 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+)
+
+func newSyslogHandler(cfg SyslogConfig, newHandler func(io.Writer) slog.Handler) (slog.Handler, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,81 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func listenSyslog(t *testing.T) *net.UDPConn {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readPacket(t *testing.T, conn *net.UDPConn) string {
+	t.Helper()
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFromUDP(buf)
+	if err != nil {
+		t.Fatalf("read syslog packet: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestSyslogOutput(t *testing.T) {
+	conn := listenSyslog(t)
+	var output bytes.Buffer
+
+	l := New(Config{
+		Format: "json",
+		Output: &output,
+		Syslog: &SyslogConfig{Network: "udp", Address: conn.LocalAddr().String(), Facility: "local0", Tag: "nexus"},
+	})
+
+	l.Info("user signed in")
+	l.Error("payment failed")
+
+	// local0 (16) * 8 + severity: info 6, err 3
+	for _, want := range []struct{ priority, msg string }{
+		{"<134>", "user signed in"},
+		{"<131>", "payment failed"},
+	} {
+		packet := readPacket(t, conn)
+		if !strings.HasPrefix(packet, want.priority) {
+			t.Errorf("packet %q, want priority %s", packet, want.priority)
+		}
+		if !strings.Contains(packet, "nexus") || !strings.Contains(packet, `"msg":"`+want.msg+`"`) {
+			t.Errorf("packet %q, want tag and message %q", packet, want.msg)
+		}
+	}
+
+	if output.Len() != 0 {
+		t.Errorf("records also went to Output: %s", output.String())
+	}
+}
+
+func TestSyslogUnknownFacilityFallsBack(t *testing.T) {
+	var output bytes.Buffer
+
+	l := New(Config{
+		Format: "json",
+		Output: &output,
+		Syslog: &SyslogConfig{Network: "udp", Address: "127.0.0.1:514", Facility: "kernel"},
+	})
+	l.Info("still logged")
+
+	if !strings.Contains(output.String(), "still logged") {
+		t.Errorf("output = %q, want records to fall back to Output", output.String())
+	}
+}
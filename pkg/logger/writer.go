@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// Re-emits lines written by libraries that log to an io.Writer (gin, the
+// standard log package) as records of the default logger, e.g.
+//
+//	gin.DefaultWriter = logger.NewSlogWriter(slog.LevelDebug)
+//
+// Partial lines are held until their newline arrives
+func NewSlogWriter(level slog.Level) io.Writer {
+	return &slogWriter{level: level}
+}
+
+type slogWriter struct {
+	level slog.Level
+	mu    sync.Mutex
+	buf   []byte
+}
+
+func (w *slogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+
+		line := bytes.TrimSpace(w.buf[:idx])
+		w.buf = w.buf[idx+1:]
+
+		if len(line) > 0 {
+			Default().Log(context.Background(), w.level, string(line))
+		}
+	}
+
+	// Don't keep the backing array of a long burst alive
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+
+	return len(p), nil
+}
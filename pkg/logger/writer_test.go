@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+// Points the default logger at a JSON buffer for the rest of the test
+func captureDefault(t *testing.T, level string) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	Init(Config{Level: level, Format: "json", Output: &buf})
+	t.Cleanup(func() { Init(Config{Output: io.Discard}) })
+	return &buf
+}
+
+// Level and message of each JSON record in buf
+func levelsAndMessages(t *testing.T, buf *bytes.Buffer) []string {
+	t.Helper()
+
+	var got []string
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("decode record %q: %v", line, err)
+		}
+		got = append(got, record["level"].(string)+" "+record["msg"].(string))
+	}
+	return got
+}
+
+func TestSlogWriterEmitsAtLevel(t *testing.T) {
+	buf := captureDefault(t, "debug")
+
+	io.WriteString(NewSlogWriter(slog.LevelDebug), "[GIN-debug] GET /api/v1/health --> handler (3 handlers)\n")
+	io.WriteString(NewSlogWriter(slog.LevelError), "[GIN] panic recovered: boom\n")
+
+	want := []string{
+		"DEBUG [GIN-debug] GET /api/v1/health --> handler (3 handlers)",
+		"ERROR [GIN] panic recovered: boom",
+	}
+	if got := levelsAndMessages(t, buf); !slices.Equal(got, want) {
+		t.Errorf("records = %q, want %q", got, want)
+	}
+}
+
+func TestSlogWriterSplitsAndBuffersLines(t *testing.T) {
+	buf := captureDefault(t, "info")
+	w := NewSlogWriter(slog.LevelInfo)
+
+	io.WriteString(w, "first line\n\n  second")
+	if got := levelsAndMessages(t, buf); !slices.Equal(got, []string{"INFO first line"}) {
+		t.Fatalf("records before the newline = %q, want only the first line", got)
+	}
+
+	n, err := io.WriteString(w, " half\n")
+	if n != len(" half\n") || err != nil {
+		t.Errorf("Write = %d, %v, want %d, nil", n, err, len(" half\n"))
+	}
+
+	want := []string{"INFO first line", "INFO second half"}
+	if got := levelsAndMessages(t, buf); !slices.Equal(got, want) {
+		t.Errorf("records = %q, want %q", got, want)
+	}
+}
+
+func TestSlogWriterRespectsLoggerLevel(t *testing.T) {
+	buf := captureDefault(t, "info")
+
+	io.WriteString(NewSlogWriter(slog.LevelDebug), "[GIN-debug] route registered\n")
+
+	if buf.Len() != 0 {
+		t.Errorf("debug line logged at info level: %s", buf.String())
+	}
+}
@@ -0,0 +1,128 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	coreNamespace      = "core"
+	moduleManifestFile = "module.yaml"
+)
+
+// Optional module.yaml in a namespace directory, e.g.
+//
+//	depends_on: [billing, users]
+type moduleManifest struct {
+	DependsOn []string `yaml:"depends_on"`
+}
+
+func (m *manager) loadModuleDeps(module string) ([]string, error) {
+	path := filepath.Join(m.migrationsDir, module, moduleManifestFile)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil // No declared dependencies
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var manifest moduleManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return manifest.DependsOn, nil
+}
+
+// Orders the enabled modules so every module comes after its dependencies,
+// keeping the given order where dependencies allow it. Core is always
+// migrated first and is an implicit dependency of every module.
+func (m *manager) resolveModuleOrder(enabledModules []string) ([]string, error) {
+	enabled := make(map[string]bool, len(enabledModules))
+	deps := make(map[string][]string, len(enabledModules))
+
+	for _, module := range enabledModules {
+		if module == coreNamespace || enabled[module] {
+			continue
+		}
+		enabled[module] = true
+
+		if info, err := os.Stat(filepath.Join(m.migrationsDir, module)); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("migration directory for module %s not found", module)
+		}
+
+		moduleDeps, err := m.loadModuleDeps(module)
+		if err != nil {
+			return nil, err
+		}
+		deps[module] = moduleDeps
+	}
+
+	for module, moduleDeps := range deps {
+		for _, dep := range moduleDeps {
+			if dep == coreNamespace {
+				continue
+			}
+			if info, err := os.Stat(filepath.Join(m.migrationsDir, dep)); err != nil || !info.IsDir() {
+				return nil, fmt.Errorf("module %s depends on %s, but its migration directory was not found", module, dep)
+			}
+			if !enabled[dep] {
+				return nil, fmt.Errorf("module %s depends on %s, which is not enabled", module, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(deps))
+	order := make([]string, 0, len(deps))
+	var path []string
+
+	var visit func(module string) error
+	visit = func(module string) error {
+		switch state[module] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("module dependency cycle: %s -> %s", strings.Join(path, " -> "), module)
+		}
+
+		state[module] = visiting
+		path = append(path, module)
+		for _, dep := range deps[module] {
+			if dep == coreNamespace {
+				continue
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[module] = visited
+
+		order = append(order, module)
+		return nil
+	}
+
+	for _, module := range enabledModules {
+		if !enabled[module] {
+			continue
+		}
+		if err := visit(module); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,128 @@
+package migration
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Writes a module with one migration creating a table named after it and,
+// when manifest is set, a module.yaml
+func writeModule(t *testing.T, dir, module, manifest string) {
+	t.Helper()
+
+	files := map[string]string{
+		"000001_init.up.sql": "CREATE TABLE " + module + " (id int);",
+	}
+	if manifest != "" {
+		files[moduleManifestFile] = manifest
+	}
+	writeMigrations(t, dir, module, files)
+}
+
+func TestResolveModuleOrderDependencyChain(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, "orders", "depends_on: [billing]")
+	writeModule(t, dir, "billing", "depends_on: [users, core]")
+	writeModule(t, dir, "users", "")
+
+	fake, db := newFakeDB(t)
+	m := NewManager(db, dir)
+
+	order, err := m.(*manager).resolveModuleOrder([]string{"orders", "billing", "users"})
+	if err != nil {
+		t.Fatalf("resolveModuleOrder: %v", err)
+	}
+	if want := []string{"users", "billing", "orders"}; !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+
+	if err := m.MigrateAll(context.Background(), []string{"orders", "billing", "users"}); err != nil {
+		t.Fatalf("MigrateAll: %v", err)
+	}
+	want := []string{
+		"CREATE TABLE users (id int);",
+		"CREATE TABLE billing (id int);",
+		"CREATE TABLE orders (id int);",
+	}
+	var created []string
+	for _, stmt := range fake.statements() {
+		if strings.HasPrefix(stmt, "CREATE TABLE") {
+			created = append(created, stmt)
+		}
+	}
+	if !slices.Equal(created, want) {
+		t.Errorf("applied = %q, want %q", created, want)
+	}
+}
+
+func TestResolveModuleOrderKeepsListOrderWithoutDeps(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, "b", "")
+	writeModule(t, dir, "a", "")
+
+	_, db := newFakeDB(t)
+	order, err := NewManager(db, dir).(*manager).resolveModuleOrder([]string{"core", "b", "a", "b"})
+	if err != nil {
+		t.Fatalf("resolveModuleOrder: %v", err)
+	}
+	if want := []string{"b", "a"}; !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestResolveModuleOrderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		modules map[string]string // module -> manifest
+		enabled []string
+		want    string
+	}{
+		{
+			name:    "cycle",
+			modules: map[string]string{"a": "depends_on: [b]", "b": "depends_on: [c]", "c": "depends_on: [a]"},
+			enabled: []string{"a", "b", "c"},
+			want:    "module dependency cycle: a -> b -> c -> a",
+		},
+		{
+			name:    "missing module directory",
+			modules: map[string]string{"a": ""},
+			enabled: []string{"a", "ghost"},
+			want:    "migration directory for module ghost not found",
+		},
+		{
+			name:    "missing dependency directory",
+			modules: map[string]string{"a": "depends_on: [ghost]"},
+			enabled: []string{"a"},
+			want:    "module a depends on ghost, but its migration directory was not found",
+		},
+		{
+			name:    "dependency not enabled",
+			modules: map[string]string{"a": "depends_on: [b]", "b": ""},
+			enabled: []string{"a"},
+			want:    "module a depends on b, which is not enabled",
+		},
+		{
+			name:    "malformed manifest",
+			modules: map[string]string{"a": "depends_on: {"},
+			enabled: []string{"a"},
+			want:    "failed to parse",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for module, manifest := range tt.modules {
+				writeModule(t, dir, module, manifest)
+			}
+
+			_, db := newFakeDB(t)
+			_, err := NewManager(db, dir).(*manager).resolveModuleOrder(tt.enabled)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
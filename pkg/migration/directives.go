@@ -0,0 +1,63 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Placed in the leading comments of an .up.sql or .down.sql file, runs that
+// file outside a transaction, e.g. for CREATE/DROP INDEX CONCURRENTLY.
+// Configured timeouts are not applied to such files
+const noTransactionDirective = "-- nexus:no-transaction"
+
+// Only the comment block at the top of the file is searched
+func hasNoTransactionDirective(sql string) bool {
+	for _, line := range strings.Split(sql, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			return false
+		}
+		if line == noTransactionDirective {
+			return true
+		}
+	}
+	return false
+}
+
+// The version stays dirty if the SQL fails halfway, there's nothing to roll back
+func (m *manager) applyMigrationNoTx(ctx context.Context, mig MigrationFile) error {
+	if err := m.setVersion(ctx, mig.Namespace, mig.Version, true); err != nil {
+		return fmt.Errorf("failed to mark as dirty: %w", err)
+	}
+
+	if _, err := m.db.ExecContext(ctx, mig.UpSQL); err != nil {
+		return fmt.Errorf("failed to execute migration SQL from %s: %w", mig.FilePath, err)
+	}
+
+	if err := m.setVersion(ctx, mig.Namespace, mig.Version, false); err != nil {
+		return fmt.Errorf("failed to mark as clean: %w", err)
+	}
+
+	return nil
+}
+
+// Marks the version dirty first, so a failure halfway is visible in Status
+func (m *manager) rollbackMigrationNoTx(ctx context.Context, mig MigrationFile) error {
+	if err := m.setVersion(ctx, mig.Namespace, mig.Version, true); err != nil {
+		return fmt.Errorf("failed to mark as dirty: %w", err)
+	}
+
+	if _, err := m.db.ExecContext(ctx, mig.DownSQL); err != nil {
+		return fmt.Errorf("failed to execute down migration from %s: %w", mig.DownFilePath, err)
+	}
+
+	if err := m.deleteVersion(ctx, mig.Namespace, mig.Version); err != nil {
+		return fmt.Errorf("failed to delete version: %w", err)
+	}
+
+	return nil
+}
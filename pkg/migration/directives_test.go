@@ -0,0 +1,90 @@
+package migration
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHasNoTransactionDirective(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want bool
+	}{
+		{"leading directive", noTransactionDirective + "\nDROP INDEX CONCURRENTLY users_email_idx;", true},
+		{"after other comments", "-- drop the lookup index\n\n  " + noTransactionDirective + "\nDROP INDEX CONCURRENTLY x;", true},
+		{"after a statement", "DROP INDEX x;\n" + noTransactionDirective, false},
+		{"similar comment", "-- nexus:no-transaction please\nDROP INDEX x;", false},
+		{"none", "DROP INDEX x;", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasNoTransactionDirective(tt.sql); got != tt.want {
+				t.Errorf("hasNoTransactionDirective = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoTransactionDownMigration(t *testing.T) {
+	dir := t.TempDir()
+	writeMigrations(t, dir, "core", map[string]string{
+		"000001_users.up.sql":         "CREATE TABLE users (email text);",
+		"000001_users.down.sql":       "DROP INDEX IF EXISTS users_tmp_idx;",
+		"000002_email_index.up.sql":   noTransactionDirective + "\nCREATE INDEX CONCURRENTLY users_email_idx ON users (email);",
+		"000002_email_index.down.sql": noTransactionDirective + "\nDROP INDEX CONCURRENTLY users_email_idx;",
+	})
+
+	fake, db := newFakeDB(t)
+	fake.applied("core", 1, 2)
+
+	if _, err := NewManager(db, dir).Rollback(context.Background(), "core", 2); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+
+	want := []string{
+		noTransactionDirective + " DROP INDEX CONCURRENTLY users_email_idx;",
+		"BEGIN",
+		"DROP INDEX IF EXISTS users_tmp_idx;",
+		"COMMIT",
+	}
+	if got := fake.statements(); !slices.Equal(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+	if got := fake.recorded("core"); len(got) != 0 {
+		t.Errorf("recorded = %v, want none", got)
+	}
+}
+
+func TestNoTransactionDownMigrationFailureLeavesDirty(t *testing.T) {
+	dir := t.TempDir()
+	writeMigrations(t, dir, "core", map[string]string{
+		"000001_email_index.up.sql":   noTransactionDirective + "\nCREATE INDEX CONCURRENTLY users_email_idx ON users (email);",
+		"000001_email_index.down.sql": noTransactionDirective + "\nDROP INDEX CONCURRENTLY users_email_idx;",
+	})
+
+	fake, db := newFakeDB(t)
+	fake.applied("core", 1)
+	fake.failOn = "DROP INDEX CONCURRENTLY"
+
+	manager := NewManager(db, dir)
+	if _, err := manager.Rollback(context.Background(), "core", 1); err == nil {
+		t.Fatal("Rollback succeeded")
+	}
+
+	status, err := manager.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if s := status["core"]; !s.Dirty || s.CurrentVersion != 1 {
+		t.Errorf("status = %+v, want version 1 left dirty", s)
+	}
+	for _, stmt := range fake.statements() {
+		if strings.HasPrefix(stmt, "BEGIN") {
+			t.Errorf("no-transaction down migration ran in a transaction: %q", fake.statements())
+		}
+	}
+}
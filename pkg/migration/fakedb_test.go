@@ -0,0 +1,255 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// database/sql driver emulating the schema_migrations table. Every other
+// statement (migration SQL, SET LOCAL, transaction control) is only recorded
+type fakeDB struct {
+	mu       sync.Mutex
+	versions map[string]map[int]bool // namespace -> version -> dirty
+	executed []string
+	// Statements containing failOn return an error
+	failOn string
+}
+
+func newFakeDB(t *testing.T) (*fakeDB, *sqlx.DB) {
+	t.Helper()
+
+	fake := &fakeDB{versions: make(map[string]map[int]bool)}
+	db := sqlx.NewDb(sql.OpenDB(fake), "postgres")
+	t.Cleanup(func() { db.Close() })
+
+	return fake, db
+}
+
+// Records versions as applied and clean
+func (f *fakeDB) applied(namespace string, versions ...int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.versions[namespace] == nil {
+		f.versions[namespace] = make(map[int]bool)
+	}
+	for _, v := range versions {
+		f.versions[namespace][v] = false
+	}
+}
+
+// Recorded versions of namespace, ascending
+func (f *fakeDB) recorded(namespace string) []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var versions []int
+	for v := range f.versions[namespace] {
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// Statements other than schema_migrations bookkeeping, whitespace collapsed
+func (f *fakeDB) statements() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.executed...)
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: f}, nil
+}
+
+func (f *fakeDB) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fake driver: use the connector")
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver: prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.record("BEGIN")
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.db.record("COMMIT")
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.db.record("ROLLBACK")
+	return nil
+}
+
+func (f *fakeDB) record(statement string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.executed = append(f.executed, statement)
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	f := c.db
+	query = strings.Join(strings.Fields(query), " ")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS schema_migrations"):
+		return driver.RowsAffected(0), nil
+
+	case strings.HasPrefix(query, "INSERT INTO schema_migrations"):
+		namespace, version := args[0].Value.(string), int(args[1].Value.(int64))
+		dirty := false
+		if d, ok := args[2].Value.(bool); ok {
+			dirty = d
+		}
+		if f.versions[namespace] == nil {
+			f.versions[namespace] = make(map[int]bool)
+		}
+		f.versions[namespace][version] = dirty
+		return driver.RowsAffected(1), nil
+
+	case strings.HasPrefix(query, "DELETE FROM schema_migrations WHERE namespace = $1 AND version = $2"):
+		delete(f.versions[args[0].Value.(string)], int(args[1].Value.(int64)))
+		return driver.RowsAffected(1), nil
+
+	case strings.HasPrefix(query, "DELETE FROM schema_migrations WHERE namespace = $1 AND version > $2"):
+		namespace, version := args[0].Value.(string), int(args[1].Value.(int64))
+		for v := range f.versions[namespace] {
+			if v > version {
+				delete(f.versions[namespace], v)
+			}
+		}
+		return driver.RowsAffected(1), nil
+	}
+
+	f.executed = append(f.executed, query)
+	if f.failOn != "" && strings.Contains(query, f.failOn) {
+		return nil, fmt.Errorf("fake driver: failing on %q", f.failOn)
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	f := c.db
+	query = strings.Join(strings.Fields(query), " ")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "SELECT version, dirty FROM schema_migrations"):
+		versions := f.versions[args[0].Value.(string)]
+		latest := -1
+		for v := range versions {
+			latest = max(latest, v)
+		}
+		if latest < 0 {
+			return &fakeRows{columns: []string{"version", "dirty"}}, nil
+		}
+		return &fakeRows{
+			columns: []string{"version", "dirty"},
+			values:  [][]driver.Value{{int64(latest), versions[latest]}},
+		}, nil
+
+	case strings.HasPrefix(query, "SELECT COUNT(*) FROM schema_migrations"):
+		count := len(f.versions[args[0].Value.(string)])
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{int64(count)}}}, nil
+
+	case strings.HasPrefix(query, "SELECT DISTINCT namespace FROM schema_migrations"):
+		rows := &fakeRows{columns: []string{"namespace"}}
+		var namespaces []string
+		for namespace, versions := range f.versions {
+			if len(versions) > 0 {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+		sort.Strings(namespaces)
+		for _, namespace := range namespaces {
+			rows.values = append(rows.values, []driver.Value{namespace})
+		}
+		return rows, nil
+
+	case strings.HasPrefix(query, "SELECT version FROM schema_migrations"):
+		rows := &fakeRows{columns: []string{"version"}}
+		var versions []int
+		for v := range f.versions[args[0].Value.(string)] {
+			versions = append(versions, v)
+		}
+		sort.Ints(versions)
+		for _, v := range versions {
+			rows.values = append(rows.values, []driver.Value{int64(v)})
+		}
+		return rows, nil
+	}
+
+	return nil, fmt.Errorf("fake driver: unexpected query %q", query)
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// Writes files (name -> content) into dir/namespace
+func writeMigrations(t *testing.T, dir, namespace string, files map[string]string) {
+	t.Helper()
+
+	nsDir := filepath.Join(dir, namespace)
+	if err := os.MkdirAll(nsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(nsDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"nexus/pkg/logger"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var nonWordPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Records version as applied and clean, dropping records of later versions.
+// Version 0 removes every record of the namespace
+func (m *manager) Force(ctx context.Context, namespace string, version int) error {
+	if version < 0 {
+		return fmt.Errorf("force version must not be negative, got %d", version)
+	}
+
+	if err := m.ensureMigrationsTable(ctx); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM schema_migrations WHERE namespace = $1 AND version > $2`
+	if _, err := tx.ExecContext(ctx, query, namespace, version); err != nil {
+		return fmt.Errorf("failed to delete later versions: %w", err)
+	}
+
+	if version > 0 {
+		upsert := `
+			INSERT INTO schema_migrations (namespace, version, dirty, applied_at)
+			VALUES ($1, $2, FALSE, $3)
+			ON CONFLICT (namespace, version)
+			DO UPDATE SET dirty = FALSE
+		`
+		if _, err := tx.ExecContext(ctx, upsert, namespace, version, time.Now()); err != nil {
+			return fmt.Errorf("failed to record version %d: %w", version, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit forced version: %w", err)
+	}
+
+	logger.FromContext(ctx).Warn("Forced migration version",
+		"namespace", namespace,
+		"version", version)
+
+	return nil
+}
+
+// Uses the default NNNNNN_description.up.sql / .down.sql naming
+func (m *manager) Create(namespace, description string) (string, string, error) {
+	name := strings.Trim(nonWordPattern.ReplaceAllString(strings.ToLower(description), "_"), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migration description %q has no usable characters", description)
+	}
+
+	migrations, err := m.loadMigrationFiles(namespace)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load migration files: %w", err)
+	}
+
+	version := 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	dir := filepath.Join(m.migrationsDir, namespace)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create migration directory: %w", err)
+	}
+
+	base := fmt.Sprintf("%06d_%s", version, name)
+	upPath := filepath.Join(dir, base+".up.sql")
+	downPath := filepath.Join(dir, base+".down.sql")
+
+	for _, path := range []string{upPath, downPath} {
+		// O_EXCL so an existing migration is never overwritten
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create migration file: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return "", "", fmt.Errorf("failed to create migration file: %w", err)
+		}
+	}
+
+	return upPath, downPath, nil
+}
@@ -0,0 +1,30 @@
+package migration
+
+import "context"
+
+// Called around migrate and rollback runs, e.g. for notifications or metrics.
+// Hooks run synchronously, slow work should be handed off
+type Hooks interface {
+	// Before the pending migrations of a namespace are applied
+	OnBeforeMigrate(ctx context.Context, namespace string, pending []MigrationFile)
+	// After the run, version is the last applied one (the starting version if none was)
+	OnAfterMigrate(ctx context.Context, namespace string, version int, err error)
+	// Before migrations are rolled back, in the order they will be reverted
+	OnBeforeRollback(ctx context.Context, namespace string, toRollback []MigrationFile)
+	// After the run, version is the one the namespace is left at
+	OnAfterRollback(ctx context.Context, namespace string, version int, err error)
+}
+
+// Embed to implement only some of the hooks
+type NoopHooks struct{}
+
+func (NoopHooks) OnBeforeMigrate(context.Context, string, []MigrationFile)  {}
+func (NoopHooks) OnAfterMigrate(context.Context, string, int, error)        {}
+func (NoopHooks) OnBeforeRollback(context.Context, string, []MigrationFile) {}
+func (NoopHooks) OnAfterRollback(context.Context, string, int, error)       {}
+
+func WithHooks(hooks Hooks) ManagerOption {
+	return func(m *manager) {
+		m.hooks = hooks
+	}
+}
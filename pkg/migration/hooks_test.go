@@ -0,0 +1,112 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// Records each hook call as "hook namespace args"
+type recordingHooks struct {
+	calls []string
+}
+
+func versionsOf(migrations []MigrationFile) []int {
+	versions := make([]int, len(migrations))
+	for i, mig := range migrations {
+		versions[i] = mig.Version
+	}
+	return versions
+}
+
+func (h *recordingHooks) OnBeforeMigrate(_ context.Context, namespace string, pending []MigrationFile) {
+	h.calls = append(h.calls, fmt.Sprintf("before migrate %s %v", namespace, versionsOf(pending)))
+}
+
+func (h *recordingHooks) OnAfterMigrate(_ context.Context, namespace string, version int, err error) {
+	h.calls = append(h.calls, fmt.Sprintf("after migrate %s %d %v", namespace, version, err != nil))
+}
+
+func (h *recordingHooks) OnBeforeRollback(_ context.Context, namespace string, toRollback []MigrationFile) {
+	h.calls = append(h.calls, fmt.Sprintf("before rollback %s %v", namespace, versionsOf(toRollback)))
+}
+
+func (h *recordingHooks) OnAfterRollback(_ context.Context, namespace string, version int, err error) {
+	h.calls = append(h.calls, fmt.Sprintf("after rollback %s %d %v", namespace, version, err != nil))
+}
+
+func TestHooksFireInOrder(t *testing.T) {
+	dir := t.TempDir()
+	writeNumberedMigrations(t, dir, 3)
+	ctx := context.Background()
+
+	hooks := &recordingHooks{}
+	_, db := newFakeDB(t)
+	manager := NewManager(db, dir, WithHooks(hooks))
+
+	if err := manager.MigrateNamespace(ctx, "core"); err != nil {
+		t.Fatalf("MigrateNamespace: %v", err)
+	}
+	if _, err := manager.Rollback(ctx, "core", 2); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if err := manager.MigrateNamespace(ctx, "core"); err != nil {
+		t.Fatalf("MigrateNamespace: %v", err)
+	}
+	// Nothing pending, no hooks
+	if err := manager.MigrateNamespace(ctx, "core"); err != nil {
+		t.Fatalf("MigrateNamespace: %v", err)
+	}
+
+	want := []string{
+		"before migrate core [1 2 3]",
+		"after migrate core 3 false",
+		"before rollback core [3 2]",
+		"after rollback core 1 false",
+		"before migrate core [2 3]",
+		"after migrate core 3 false",
+	}
+	if !slices.Equal(hooks.calls, want) {
+		t.Errorf("calls = %q, want %q", hooks.calls, want)
+	}
+}
+
+func TestHooksReportFailures(t *testing.T) {
+	dir := t.TempDir()
+	writeNumberedMigrations(t, dir, 3)
+	ctx := context.Background()
+
+	hooks := &recordingHooks{}
+	fake, db := newFakeDB(t)
+	fake.failOn = "CREATE TABLE table_2"
+
+	if err := NewManager(db, dir, WithHooks(hooks)).MigrateNamespace(ctx, "core"); err == nil {
+		t.Fatal("MigrateNamespace succeeded")
+	}
+
+	want := []string{
+		"before migrate core [1 2 3]",
+		"after migrate core 1 true",
+	}
+	if !slices.Equal(hooks.calls, want) {
+		t.Errorf("migrate calls = %q, want %q", hooks.calls, want)
+	}
+
+	hooks = &recordingHooks{}
+	fake, db = newFakeDB(t)
+	fake.applied("core", 1, 2, 3)
+	fake.failOn = "DROP INDEX IF EXISTS table_2_idx"
+
+	if _, err := NewManager(db, dir, WithHooks(hooks)).Rollback(ctx, "core", 2); err == nil {
+		t.Fatal("Rollback succeeded")
+	}
+
+	want = []string{
+		"before rollback core [3 2]",
+		"after rollback core 2 true",
+	}
+	if !slices.Equal(hooks.calls, want) {
+		t.Errorf("rollback calls = %q, want %q", hooks.calls, want)
+	}
+}
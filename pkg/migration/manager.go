@@ -1,12 +1,15 @@
 package migration
 
 import (
+	"compress/gzip"
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
 	"nexus/pkg/logger"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -17,12 +20,25 @@ import (
 
 type Manager interface {
 	MigrateNamespace(ctx context.Context, namespace string) error
-	// Applies all pending migrations (core + enabled modules)
+	// Applies all pending migrations (core + enabled modules, ordered by the
+	// depends_on list in each module's module.yaml)
 	MigrateAll(ctx context.Context, enabledModules []string) error
-	Rollback(ctx context.Context, namespace string, steps int) error
+	// Refuses down migrations that drop tables or columns unless AllowDataLoss is given.
+	// Returns how many migrations were rolled back, fewer than steps when the
+	// namespace runs out of applied ones
+	Rollback(ctx context.Context, namespace string, steps int, opts ...RollbackOption) (int, error)
 	Version(ctx context.Context, namespace string) (int, error)
+	// Marks migrations up to version as applied without running them (existing schema)
+	Baseline(ctx context.Context, namespace string, version int) error
+	// Sets the recorded version and clears the dirty flag without running SQL,
+	// for recovering after a failed migration was fixed by hand
+	Force(ctx context.Context, namespace string, version int) error
+	// Creates empty up/down files for the next version, returns their paths
+	Create(namespace, description string) (upPath, downPath string, err error)
 	// Returns migration status for all namespaces
 	Status(ctx context.Context) (map[string]MigrationStatus, error)
+	// Same as Status, but also includes the pending migrations with their SQL
+	StatusVerbose(ctx context.Context) (map[string]MigrationStatus, error)
 }
 
 type MigrationStatus struct {
@@ -30,6 +46,11 @@ type MigrationStatus struct {
 	CurrentVersion int
 	PendingCount   int
 	Dirty          bool
+	// Only filled by StatusVerbose, in the order they would be applied
+	Pending []MigrationFile
+	// Versions recorded as applied without an up file on disk (deleted or
+	// renamed by accident, or recorded by Baseline/Force), ascending
+	MissingFiles []int
 }
 
 type MigrationFile struct {
@@ -38,18 +59,99 @@ type MigrationFile struct {
 	Namespace   string
 	UpSQL       string
 	DownSQL     string
+	// Source files, for error messages
+	FilePath     string
+	DownFilePath string
 }
 
+// Postgres timeouts set with SET LOCAL before each migration, zero leaves the server setting
+type Timeouts struct {
+	// How long to wait for a lock before failing instead of queueing behind traffic
+	Lock time.Duration
+	// Maximum run time of a single statement
+	Statement time.Duration
+}
+
+// Migration filename pattern, must capture the "version" and "description"
+// groups. An optional "direction" group marks down migrations with "down",
+// files without it are up migrations
+var DefaultFilenamePattern = regexp.MustCompile(`^(?P<version>\d+)_(?P<description>.+)\.(?P<direction>up|down)\.sql$`)
+
+// Flyway-style V0001__description.sql, up migrations only
+var FlywayFilenamePattern = regexp.MustCompile(`^V(?P<version>\d+)__(?P<description>.+)\.sql$`)
+
 type manager struct {
-	db            *sqlx.DB
-	migrationsDir string
+	db                *sqlx.DB
+	migrationsDir     string
+	timeouts          Timeouts
+	namespaceTimeouts map[string]Timeouts
+	filenamePattern   *regexp.Regexp
+	hooks             Hooks
+}
+
+type ManagerOption func(*manager)
+
+// Timeouts for every namespace without its own override
+func WithTimeouts(timeouts Timeouts) ManagerOption {
+	return func(m *manager) {
+		m.timeouts = timeouts
+	}
+}
+
+// Parses migration filenames with pattern instead of DefaultFilenamePattern
+func WithFilenamePattern(pattern *regexp.Regexp) ManagerOption {
+	return func(m *manager) {
+		m.filenamePattern = pattern
+	}
+}
+
+// Overrides the timeouts for a single namespace
+func WithNamespaceTimeouts(namespace string, timeouts Timeouts) ManagerOption {
+	return func(m *manager) {
+		m.namespaceTimeouts[namespace] = timeouts
+	}
+}
+
+func NewManager(db *sqlx.DB, migrationsDir string, opts ...ManagerOption) Manager {
+	m := &manager{
+		db:                db,
+		migrationsDir:     migrationsDir,
+		namespaceTimeouts: make(map[string]Timeouts),
+		filenamePattern:   DefaultFilenamePattern,
+		hooks:             NoopHooks{},
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
 }
 
-func NewManager(db *sqlx.DB, migrationsDir string) Manager {
-	return &manager{
-		db:            db,
-		migrationsDir: migrationsDir,
+func (m *manager) timeoutsFor(namespace string) Timeouts {
+	if timeouts, ok := m.namespaceTimeouts[namespace]; ok {
+		return timeouts
+	}
+	return m.timeouts
+}
+
+// SET doesn't accept bind parameters, values are formatted as milliseconds
+func setLocalTimeouts(ctx context.Context, tx *sqlx.Tx, timeouts Timeouts) error {
+	if timeouts.Lock > 0 {
+		query := fmt.Sprintf("SET LOCAL lock_timeout = '%dms'", timeouts.Lock.Milliseconds())
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to set lock_timeout: %w", err)
+		}
+	}
+
+	if timeouts.Statement > 0 {
+		query := fmt.Sprintf("SET LOCAL statement_timeout = '%dms'", timeouts.Statement.Milliseconds())
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to set statement_timeout: %w", err)
+		}
 	}
+
+	return nil
 }
 
 func (m *manager) ensureMigrationsTable(ctx context.Context) error {
@@ -120,6 +222,13 @@ func (m *manager) loadMigrationFiles(namespace string) ([]MigrationFile, error)
 		return []MigrationFile{}, nil // No migrations for this namespace
 	}
 
+	versionGroup := m.filenamePattern.SubexpIndex("version")
+	descriptionGroup := m.filenamePattern.SubexpIndex("description")
+	directionGroup := m.filenamePattern.SubexpIndex("direction")
+	if versionGroup < 0 || descriptionGroup < 0 {
+		return nil, fmt.Errorf("filename pattern %s must capture version and description", m.filenamePattern)
+	}
+
 	files, err := os.ReadDir(namespacePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read migration directory: %w", err)
@@ -127,55 +236,62 @@ func (m *manager) loadMigrationFiles(namespace string) ([]MigrationFile, error)
 
 	migrations := make(map[int]*MigrationFile)
 
+	// Plain files take precedence over their compressed copy
+	plainFiles := make(map[string]bool)
+	for _, file := range files {
+		if !file.IsDir() && !strings.HasSuffix(file.Name(), ".gz") {
+			plainFiles[file.Name()] = true
+		}
+	}
+
 	for _, file := range files {
 		if file.IsDir() {
 			continue
 		}
 
 		filename := file.Name()
+		compressed := strings.HasSuffix(filename, ".gz")
+		if compressed {
+			filename = strings.TrimSuffix(filename, ".gz")
+			if plainFiles[filename] {
+				continue
+			}
+		}
 
-		// Parse filename: 000001_description.up.sql or 000001_description.down.sql
-		parts := strings.Split(filename, "_")
-		if len(parts) < 2 {
+		// Default pattern: 000001_description.up.sql or 000001_description.down.sql
+		match := m.filenamePattern.FindStringSubmatch(filename)
+		if match == nil {
 			continue
 		}
 
-		version, err := strconv.Atoi(parts[0])
+		version, err := strconv.Atoi(match[versionGroup])
 		if err != nil {
 			continue
 		}
 
+		down := directionGroup >= 0 && match[directionGroup] == "down"
+
 		// Read file content
-		content, err := os.ReadFile(filepath.Join(namespacePath, filename))
+		content, err := readMigrationFile(filepath.Join(namespacePath, file.Name()), compressed)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read migration file %s: %w", filename, err)
+			return nil, fmt.Errorf("failed to read migration file %s: %w", file.Name(), err)
 		}
 
-		// Determine if it's up or down migration
-		if strings.HasSuffix(filename, ".up.sql") {
-			if migrations[version] == nil {
-				migrations[version] = &MigrationFile{
-					Version:   version,
-					Namespace: namespace,
-				}
+		if migrations[version] == nil {
+			migrations[version] = &MigrationFile{
+				Version:   version,
+				Namespace: namespace,
 			}
-			migrations[version].UpSQL = string(content)
+		}
 
-			// Extract description from filename
-			descParts := strings.Split(filename, "_")
-			if len(descParts) > 1 {
-				desc := strings.Join(descParts[1:], "_")
-				desc = strings.TrimSuffix(desc, ".up.sql")
-				migrations[version].Description = desc
-			}
-		} else if strings.HasSuffix(filename, ".down.sql") {
-			if migrations[version] == nil {
-				migrations[version] = &MigrationFile{
-					Version:   version,
-					Namespace: namespace,
-				}
-			}
+		// Determine if it's up or down migration
+		if down {
 			migrations[version].DownSQL = string(content)
+			migrations[version].DownFilePath = filepath.Join(namespacePath, file.Name())
+		} else {
+			migrations[version].UpSQL = string(content)
+			migrations[version].FilePath = filepath.Join(namespacePath, file.Name())
+			migrations[version].Description = match[descriptionGroup]
 		}
 	}
 
@@ -192,6 +308,26 @@ func (m *manager) loadMigrationFiles(namespace string) ([]MigrationFile, error)
 	return result, nil
 }
 
+func readMigrationFile(path string, compressed bool) ([]byte, error) {
+	if !compressed {
+		return os.ReadFile(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	return io.ReadAll(gz)
+}
+
 func (m *manager) MigrateNamespace(ctx context.Context, namespace string) error {
 	log := logger.FromContext(ctx)
 
@@ -237,11 +373,17 @@ func (m *manager) MigrateNamespace(ctx context.Context, namespace string) error
 		"current_version", currentVersion,
 		"pending_count", len(pending))
 
+	m.hooks.OnBeforeMigrate(ctx, namespace, pending)
+
 	// Apply each pending migration
+	applied := currentVersion
 	for _, mig := range pending {
 		if err := m.applyMigration(ctx, mig); err != nil {
-			return fmt.Errorf("failed to apply migration %d: %w", mig.Version, err)
+			err = fmt.Errorf("failed to apply migration %d: %w", mig.Version, err)
+			m.hooks.OnAfterMigrate(ctx, namespace, applied, err)
+			return err
 		}
+		applied = mig.Version
 
 		log.Info("Applied migration",
 			"namespace", namespace,
@@ -249,11 +391,17 @@ func (m *manager) MigrateNamespace(ctx context.Context, namespace string) error
 			"description", mig.Description)
 	}
 
+	m.hooks.OnAfterMigrate(ctx, namespace, applied, nil)
+
 	log.Info("All migrations applied successfully", "namespace", namespace)
 	return nil
 }
 
 func (m *manager) applyMigration(ctx context.Context, mig MigrationFile) error {
+	if hasNoTransactionDirective(mig.UpSQL) {
+		return m.applyMigrationNoTx(ctx, mig)
+	}
+
 	tx, err := m.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -270,9 +418,14 @@ func (m *manager) applyMigration(ctx context.Context, mig MigrationFile) error {
 		return fmt.Errorf("failed to mark as dirty: %w", err)
 	}
 
+	// Fail fast rather than block application queries
+	if err = setLocalTimeouts(ctx, tx, m.timeoutsFor(mig.Namespace)); err != nil {
+		return err
+	}
+
 	// Execute migration
 	if _, err = tx.ExecContext(ctx, mig.UpSQL); err != nil {
-		return fmt.Errorf("failed to execute migration SQL: %w", err)
+		return fmt.Errorf("failed to execute migration SQL from %s: %w", mig.FilePath, err)
 	}
 
 	// Mark as clean
@@ -289,13 +442,20 @@ func (m *manager) MigrateAll(ctx context.Context, enabledModules []string) error
 
 	log.Info("Starting migrations", "enabled_modules", enabledModules)
 
+	// Resolve the order up front, so a bad dependency graph fails before
+	// anything is applied
+	modules, err := m.resolveModuleOrder(enabledModules)
+	if err != nil {
+		return fmt.Errorf("failed to resolve module order: %w", err)
+	}
+
 	// Always migrate core first
-	if err := m.MigrateNamespace(ctx, "core"); err != nil {
+	if err := m.MigrateNamespace(ctx, coreNamespace); err != nil {
 		return fmt.Errorf("failed to migrate core: %w", err)
 	}
 
-	// Migrate each enabled module
-	for _, module := range enabledModules {
+	// Migrate each enabled module after its dependencies
+	for _, module := range modules {
 		if err := m.MigrateNamespace(ctx, module); err != nil {
 			return fmt.Errorf("failed to migrate module %s: %w", module, err)
 		}
@@ -305,26 +465,31 @@ func (m *manager) MigrateAll(ctx context.Context, enabledModules []string) error
 	return nil
 }
 
-func (m *manager) Rollback(ctx context.Context, namespace string, steps int) error {
+func (m *manager) Rollback(ctx context.Context, namespace string, steps int, opts ...RollbackOption) (int, error) {
 	log := logger.FromContext(ctx)
 
+	var options rollbackOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	currentVersion, dirty, err := m.getCurrentVersion(ctx, namespace)
 	if err != nil {
-		return fmt.Errorf("failed to get current version: %w", err)
+		return 0, fmt.Errorf("failed to get current version: %w", err)
 	}
 
 	if dirty {
-		return fmt.Errorf("namespace %s is in dirty state at version %d", namespace, currentVersion)
+		return 0, fmt.Errorf("namespace %s is in dirty state at version %d", namespace, currentVersion)
 	}
 
 	if currentVersion == 0 {
 		log.Info("No migrations to rollback", "namespace", namespace)
-		return nil
+		return 0, nil
 	}
 
 	migrations, err := m.loadMigrationFiles(namespace)
 	if err != nil {
-		return fmt.Errorf("failed to load migration files: %w", err)
+		return 0, fmt.Errorf("failed to load migration files: %w", err)
 	}
 
 	// Find migrations to rollback (in reverse order)
@@ -337,7 +502,7 @@ func (m *manager) Rollback(ctx context.Context, namespace string, steps int) err
 
 	if len(toRollback) == 0 {
 		log.Info("No migrations to rollback", "namespace", namespace)
-		return nil
+		return 0, nil
 	}
 
 	log.Info("Rolling back migrations",
@@ -345,11 +510,24 @@ func (m *manager) Rollback(ctx context.Context, namespace string, steps int) err
 		"current_version", currentVersion,
 		"steps", len(toRollback))
 
+	// Checked up front, so nothing is rolled back when one of them is refused
+	if !options.allowDataLoss {
+		if err := checkDestructive(toRollback); err != nil {
+			return 0, err
+		}
+	}
+
+	m.hooks.OnBeforeRollback(ctx, namespace, toRollback)
+
 	// Rollback each migration
-	for _, mig := range toRollback {
+	remaining := currentVersion
+	for i, mig := range toRollback {
 		if err := m.rollbackMigration(ctx, mig); err != nil {
-			return fmt.Errorf("failed to rollback migration %d: %w", mig.Version, err)
+			err = fmt.Errorf("failed to rollback migration %d: %w", mig.Version, err)
+			m.hooks.OnAfterRollback(ctx, namespace, remaining, err)
+			return i, err
 		}
+		remaining = previousVersion(migrations, mig.Version)
 
 		log.Info("Rolled back migration",
 			"namespace", namespace,
@@ -357,10 +535,33 @@ func (m *manager) Rollback(ctx context.Context, namespace string, steps int) err
 			"description", mig.Description)
 	}
 
-	return nil
+	m.hooks.OnAfterRollback(ctx, namespace, remaining, nil)
+
+	return len(toRollback), nil
+}
+
+// Highest version below the given one, 0 when there is none.
+// migrations must be sorted by version
+func previousVersion(migrations []MigrationFile, version int) int {
+	previous := 0
+	for _, mig := range migrations {
+		if mig.Version >= version {
+			break
+		}
+		previous = mig.Version
+	}
+	return previous
 }
 
 func (m *manager) rollbackMigration(ctx context.Context, mig MigrationFile) error {
+	if mig.DownSQL == "" {
+		return fmt.Errorf("no down migration found for version %d", mig.Version)
+	}
+
+	if hasNoTransactionDirective(mig.DownSQL) {
+		return m.rollbackMigrationNoTx(ctx, mig)
+	}
+
 	tx, err := m.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -373,12 +574,8 @@ func (m *manager) rollbackMigration(ctx context.Context, mig MigrationFile) erro
 	}()
 
 	// Execute down migration
-	if mig.DownSQL == "" {
-		return fmt.Errorf("no down migration found for version %d", mig.Version)
-	}
-
 	if _, err = tx.ExecContext(ctx, mig.DownSQL); err != nil {
-		return fmt.Errorf("failed to execute down migration: %w", err)
+		return fmt.Errorf("failed to execute down migration from %s: %w", mig.DownFilePath, err)
 	}
 
 	// Delete version record
@@ -394,8 +591,79 @@ func (m *manager) Version(ctx context.Context, namespace string) (int, error) {
 	return version, err
 }
 
+// Records migrations up to version as applied without executing their SQL,
+// for adopting the tool on a database that already has the schema
+func (m *manager) Baseline(ctx context.Context, namespace string, version int) error {
+	if version <= 0 {
+		return fmt.Errorf("baseline version must be positive, got %d", version)
+	}
+
+	if err := m.ensureMigrationsTable(ctx); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	var count int
+	query := `SELECT COUNT(*) FROM schema_migrations WHERE namespace = $1`
+	if err := m.db.QueryRowContext(ctx, query, namespace).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check existing migrations: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("namespace %s already has %d applied migrations, refusing to baseline", namespace, count)
+	}
+
+	migrations, err := m.loadMigrationFiles(namespace)
+	if err != nil {
+		return fmt.Errorf("failed to load migration files: %w", err)
+	}
+
+	versions := []int{}
+	for _, mig := range migrations {
+		if mig.Version < version {
+			versions = append(versions, mig.Version)
+		}
+	}
+	versions = append(versions, version)
+
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insert := `
+		INSERT INTO schema_migrations (namespace, version, dirty, applied_at)
+		VALUES ($1, $2, FALSE, $3)
+	`
+	now := time.Now()
+	for _, v := range versions {
+		if _, err := tx.ExecContext(ctx, insert, namespace, v, now); err != nil {
+			return fmt.Errorf("failed to record baseline version %d: %w", v, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit baseline: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("Baselined namespace",
+		"namespace", namespace,
+		"version", version,
+		"recorded", len(versions))
+
+	return nil
+}
+
 // Returns migration status for all namespaces
 func (m *manager) Status(ctx context.Context) (map[string]MigrationStatus, error) {
+	return m.status(ctx, false)
+}
+
+// Returns migration status for all namespaces including the pending migrations SQL
+func (m *manager) StatusVerbose(ctx context.Context) (map[string]MigrationStatus, error) {
+	return m.status(ctx, true)
+}
+
+func (m *manager) status(ctx context.Context, includePending bool) (map[string]MigrationStatus, error) {
 	// Ensure migrations table exists
 	if err := m.ensureMigrationsTable(ctx); err != nil {
 		return nil, fmt.Errorf("failed to create migrations table: %w", err)
@@ -450,10 +718,19 @@ func (m *manager) Status(ctx context.Context) (map[string]MigrationStatus, error
 			return nil, err
 		}
 
+		missing, err := m.missingFiles(ctx, namespace, migrations)
+		if err != nil {
+			return nil, err
+		}
+
 		pendingCount := 0
+		var pending []MigrationFile
 		for _, mig := range migrations {
 			if mig.Version > currentVersion {
 				pendingCount++
+				if includePending {
+					pending = append(pending, mig)
+				}
 			}
 		}
 
@@ -462,8 +739,35 @@ func (m *manager) Status(ctx context.Context) (map[string]MigrationStatus, error
 			CurrentVersion: currentVersion,
 			PendingCount:   pendingCount,
 			Dirty:          dirty,
+			Pending:        pending,
+			MissingFiles:   missing,
 		}
 	}
 
 	return result, nil
 }
+
+// Recorded versions without a matching up migration file
+func (m *manager) missingFiles(ctx context.Context, namespace string, migrations []MigrationFile) ([]int, error) {
+	var recorded []int
+	query := `SELECT version FROM schema_migrations WHERE namespace = $1 ORDER BY version`
+	if err := m.db.SelectContext(ctx, &recorded, query, namespace); err != nil {
+		return nil, fmt.Errorf("failed to load applied versions: %w", err)
+	}
+
+	onDisk := make(map[int]bool, len(migrations))
+	for _, mig := range migrations {
+		if mig.UpSQL != "" || mig.FilePath != "" {
+			onDisk[mig.Version] = true
+		}
+	}
+
+	var missing []int
+	for _, version := range recorded {
+		if !onDisk[version] {
+			missing = append(missing, version)
+		}
+	}
+
+	return missing, nil
+}
@@ -0,0 +1,380 @@
+package migration
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Writes migrations 1..n to dir/core, each creating table_<version>
+func writeNumberedMigrations(t *testing.T, dir string, n int) {
+	t.Helper()
+
+	files := make(map[string]string)
+	for v := 1; v <= n; v++ {
+		files[fmt.Sprintf("%06d_table_%d.up.sql", v, v)] = fmt.Sprintf("CREATE TABLE table_%d (id int);", v)
+		files[fmt.Sprintf("%06d_table_%d.down.sql", v, v)] = fmt.Sprintf("DROP INDEX IF EXISTS table_%d_idx;", v)
+	}
+	writeMigrations(t, dir, "core", files)
+}
+
+// Migration SQL that ran, in order
+func createdTables(fake *fakeDB) []string {
+	var created []string
+	for _, stmt := range fake.statements() {
+		if strings.HasPrefix(stmt, "CREATE TABLE table_") {
+			created = append(created, stmt)
+		}
+	}
+	return created
+}
+
+func TestBaselineAppliesOnlyLaterMigrations(t *testing.T) {
+	dir := t.TempDir()
+	writeNumberedMigrations(t, dir, 5)
+
+	fake, db := newFakeDB(t)
+	manager := NewManager(db, dir)
+	ctx := context.Background()
+
+	if err := manager.Baseline(ctx, "core", 3); err != nil {
+		t.Fatalf("Baseline: %v", err)
+	}
+	if got := createdTables(fake); len(got) != 0 {
+		t.Fatalf("Baseline ran migration SQL: %q", got)
+	}
+	if got := fake.recorded("core"); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("recorded after baseline = %v, want [1 2 3]", got)
+	}
+
+	if err := manager.MigrateNamespace(ctx, "core"); err != nil {
+		t.Fatalf("MigrateNamespace: %v", err)
+	}
+
+	want := []string{"CREATE TABLE table_4 (id int);", "CREATE TABLE table_5 (id int);"}
+	if got := createdTables(fake); !slices.Equal(got, want) {
+		t.Errorf("applied = %q, want %q", got, want)
+	}
+	if got := fake.recorded("core"); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("recorded = %v, want [1 2 3 4 5]", got)
+	}
+}
+
+func TestBaselineRefusesAppliedNamespace(t *testing.T) {
+	dir := t.TempDir()
+	writeNumberedMigrations(t, dir, 2)
+
+	fake, db := newFakeDB(t)
+	fake.applied("core", 1)
+
+	err := NewManager(db, dir).Baseline(context.Background(), "core", 2)
+	if err == nil || !strings.Contains(err.Error(), "refusing to baseline") {
+		t.Errorf("err = %v, want a refusal", err)
+	}
+}
+
+// Writes a gzip-compressed migration file into dir/namespace
+func writeGzipMigration(t *testing.T, dir, namespace, name, content string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	nsDir := filepath.Join(dir, namespace)
+	if err := os.MkdirAll(nsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(nsDir, name), buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGzipMigrationAppliesLikePlain(t *testing.T) {
+	const up = "CREATE TABLE orders (id int);"
+	const down = "DROP TABLE orders;"
+
+	plainDir := t.TempDir()
+	writeMigrations(t, plainDir, "core", map[string]string{
+		"000001_create_orders.up.sql":   up,
+		"000001_create_orders.down.sql": down,
+	})
+
+	gzipDir := t.TempDir()
+	writeGzipMigration(t, gzipDir, "core", "000001_create_orders.up.sql.gz", up)
+	writeGzipMigration(t, gzipDir, "core", "000001_create_orders.down.sql.gz", down)
+
+	apply := func(dir string) (*fakeDB, MigrationFile) {
+		fake, db := newFakeDB(t)
+		m := NewManager(db, dir)
+
+		files, err := m.(*manager).loadMigrationFiles("core")
+		if err != nil || len(files) != 1 {
+			t.Fatalf("loadMigrationFiles = %v, %v, want one migration", files, err)
+		}
+		if err := m.MigrateNamespace(context.Background(), "core"); err != nil {
+			t.Fatalf("MigrateNamespace: %v", err)
+		}
+		return fake, files[0]
+	}
+
+	plainFake, plain := apply(plainDir)
+	gzipFake, compressed := apply(gzipDir)
+
+	if compressed.Version != 1 || compressed.Description != "create_orders" {
+		t.Errorf("parsed = %d %q, want 1 %q", compressed.Version, compressed.Description, "create_orders")
+	}
+	if compressed.UpSQL != plain.UpSQL || compressed.DownSQL != plain.DownSQL {
+		t.Errorf("gzip SQL = %q/%q, want %q/%q", compressed.UpSQL, compressed.DownSQL, plain.UpSQL, plain.DownSQL)
+	}
+	if got, want := gzipFake.statements(), plainFake.statements(); !slices.Equal(got, want) {
+		t.Errorf("gzip statements = %q, want %q", got, want)
+	}
+	if got := gzipFake.recorded("core"); !slices.Equal(got, []int{1}) {
+		t.Errorf("recorded = %v, want [1]", got)
+	}
+}
+
+func TestPlainMigrationWinsOverGzip(t *testing.T) {
+	dir := t.TempDir()
+	writeMigrations(t, dir, "core", map[string]string{
+		"000001_create_orders.up.sql": "CREATE TABLE orders (id int);",
+	})
+	writeGzipMigration(t, dir, "core", "000001_create_orders.up.sql.gz", "CREATE TABLE stale (id int);")
+
+	_, db := newFakeDB(t)
+	files, err := NewManager(db, dir).(*manager).loadMigrationFiles("core")
+	if err != nil {
+		t.Fatalf("loadMigrationFiles: %v", err)
+	}
+	if len(files) != 1 || files[0].UpSQL != "CREATE TABLE orders (id int);" {
+		t.Errorf("files = %+v, want the plain file only", files)
+	}
+}
+
+func TestApplyMigrationSetsTimeoutsFirst(t *testing.T) {
+	dir := t.TempDir()
+	writeMigrations(t, dir, "core", map[string]string{
+		"000001_create_orders.up.sql": "CREATE TABLE orders (id int);",
+	})
+	writeMigrations(t, dir, "billing", map[string]string{
+		"000001_create_invoices.up.sql": "CREATE TABLE invoices (id int);",
+	})
+
+	fake, db := newFakeDB(t)
+	manager := NewManager(db, dir,
+		WithTimeouts(Timeouts{Lock: 2 * time.Second, Statement: 30 * time.Second}),
+		WithNamespaceTimeouts("billing", Timeouts{Lock: 5 * time.Second}))
+
+	if err := manager.MigrateAll(context.Background(), []string{"billing"}); err != nil {
+		t.Fatalf("MigrateAll: %v", err)
+	}
+
+	want := []string{
+		"BEGIN",
+		"SET LOCAL lock_timeout = '2000ms'",
+		"SET LOCAL statement_timeout = '30000ms'",
+		"CREATE TABLE orders (id int);",
+		"COMMIT",
+		"BEGIN",
+		"SET LOCAL lock_timeout = '5000ms'",
+		"CREATE TABLE invoices (id int);",
+		"COMMIT",
+	}
+	if got := fake.statements(); !slices.Equal(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+}
+
+func TestApplyMigrationWithoutTimeouts(t *testing.T) {
+	dir := t.TempDir()
+	writeNumberedMigrations(t, dir, 1)
+
+	fake, db := newFakeDB(t)
+	if err := NewManager(db, dir).MigrateNamespace(context.Background(), "core"); err != nil {
+		t.Fatalf("MigrateNamespace: %v", err)
+	}
+
+	for _, stmt := range fake.statements() {
+		if strings.HasPrefix(stmt, "SET LOCAL") {
+			t.Errorf("unexpected %q without configured timeouts", stmt)
+		}
+	}
+}
+
+func TestMigrationErrorsNameTheFile(t *testing.T) {
+	dir := t.TempDir()
+	writeNumberedMigrations(t, dir, 2)
+	ctx := context.Background()
+
+	fake, db := newFakeDB(t)
+	fake.failOn = "CREATE TABLE table_2"
+	err := NewManager(db, dir).MigrateNamespace(ctx, "core")
+	if want := filepath.Join(dir, "core", "000002_table_2.up.sql"); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("MigrateNamespace err = %v, want it to name %s", err, want)
+	}
+
+	fake, db = newFakeDB(t)
+	fake.applied("core", 1, 2)
+	fake.failOn = "DROP INDEX IF EXISTS table_2_idx"
+	_, err = NewManager(db, dir).Rollback(ctx, "core", 1)
+	if want := filepath.Join(dir, "core", "000002_table_2.down.sql"); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Rollback err = %v, want it to name %s", err, want)
+	}
+}
+
+func TestRollbackReturnsCount(t *testing.T) {
+	dir := t.TempDir()
+	writeNumberedMigrations(t, dir, 3)
+
+	tests := []struct {
+		name    string
+		applied []int
+		steps   int
+		want    int
+	}{
+		{"fewer than applied", []int{1, 2, 3}, 2, 2},
+		{"more than applied", []int{1, 2}, 5, 2},
+		{"nothing applied", nil, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			fake.applied("core", tt.applied...)
+
+			got, err := NewManager(db, dir).Rollback(context.Background(), "core", tt.steps)
+			if err != nil {
+				t.Fatalf("Rollback: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("rolled back = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMigrationFilesPatterns(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   *regexp.Regexp
+		files     map[string]string
+		wantDescs []string // Per version, ascending
+		wantDown  string   // Down SQL of the first version
+	}{
+		{
+			name:    "default",
+			pattern: DefaultFilenamePattern,
+			files: map[string]string{
+				"000001_create_users.up.sql":   "CREATE TABLE users (id int);",
+				"000001_create_users.down.sql": "DROP TABLE users;",
+				"000002_add_index.up.sql":      "CREATE INDEX users_id ON users (id);",
+				"V0003__flyway.sql":            "SELECT 1;",
+				"README.md":                    "notes",
+			},
+			wantDescs: []string{"create_users", "add_index"},
+			wantDown:  "DROP TABLE users;",
+		},
+		{
+			name:    "flyway",
+			pattern: FlywayFilenamePattern,
+			files: map[string]string{
+				"V0001__create_users.sql":     "CREATE TABLE users (id int);",
+				"V0002__add_index.sql":        "CREATE INDEX users_id ON users (id);",
+				"000003_default.up.sql":       "SELECT 1;",
+				"V0004_single_underscore.sql": "SELECT 1;",
+			},
+			wantDescs: []string{"create_users", "add_index"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeMigrations(t, dir, "core", tt.files)
+
+			_, db := newFakeDB(t)
+			files, err := NewManager(db, dir, WithFilenamePattern(tt.pattern)).(*manager).loadMigrationFiles("core")
+			if err != nil {
+				t.Fatalf("loadMigrationFiles: %v", err)
+			}
+
+			var descs []string
+			for i, f := range files {
+				if f.Version != i+1 {
+					t.Errorf("files[%d].Version = %d, want %d", i, f.Version, i+1)
+				}
+				if f.UpSQL == "" {
+					t.Errorf("version %d has no up SQL", f.Version)
+				}
+				descs = append(descs, f.Description)
+			}
+			if !slices.Equal(descs, tt.wantDescs) {
+				t.Errorf("descriptions = %q, want %q", descs, tt.wantDescs)
+			}
+			if len(files) > 0 && files[0].DownSQL != tt.wantDown {
+				t.Errorf("down SQL = %q, want %q", files[0].DownSQL, tt.wantDown)
+			}
+		})
+	}
+}
+
+func TestLoadMigrationFilesPatternNeedsGroups(t *testing.T) {
+	dir := t.TempDir()
+	writeNumberedMigrations(t, dir, 1)
+
+	_, db := newFakeDB(t)
+	pattern := regexp.MustCompile(`^(\d+)_(.+)\.sql$`)
+	_, err := NewManager(db, dir, WithFilenamePattern(pattern)).(*manager).loadMigrationFiles("core")
+	if err == nil || !strings.Contains(err.Error(), "must capture version and description") {
+		t.Errorf("err = %v, want a missing group error", err)
+	}
+}
+
+func TestStatusReportsMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeNumberedMigrations(t, dir, 3)
+	writeMigrations(t, dir, "billing", map[string]string{
+		"000001_invoices.up.sql": "CREATE TABLE invoices (id int);",
+	})
+	// Deleted after being applied
+	for _, name := range []string{"000002_table_2.up.sql", "000002_table_2.down.sql"} {
+		if err := os.Remove(filepath.Join(dir, "core", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fake, db := newFakeDB(t)
+	fake.applied("core", 1, 2, 3, 4)
+	fake.applied("billing", 1)
+	fake.applied("legacy", 1, 2) // Whole namespace directory removed
+
+	status, err := NewManager(db, dir).Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+
+	want := map[string][]int{
+		"core":    {2, 4},
+		"billing": nil,
+		"legacy":  {1, 2},
+	}
+	for namespace, missing := range want {
+		if got := status[namespace].MissingFiles; !slices.Equal(got, missing) {
+			t.Errorf("%s MissingFiles = %v, want %v", namespace, got, missing)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	destructivePattern = regexp.MustCompile(`(?i)\b(DROP\s+TABLE|DROP\s+COLUMN|DROP\s+SCHEMA|TRUNCATE)\b`)
+	alterTablePattern  = regexp.MustCompile(`(?i)\bALTER\s+TABLE\b`)
+	alterDropPattern   = regexp.MustCompile(`(?i)\bDROP\s+(\w+)`)
+	commentPattern     = regexp.MustCompile(`(?s)--[^\n]*|/\*.*?\*/`)
+
+	ErrDestructiveRollback = errors.New("down migration drops data")
+)
+
+// ALTER TABLE ... DROP subcommands that keep the data
+var nonDestructiveDrops = map[string]bool{
+	"CONSTRAINT": true,
+	"DEFAULT":    true,
+	"NOT":        true, // DROP NOT NULL
+	"IDENTITY":   true,
+	"EXPRESSION": true,
+}
+
+type rollbackOptions struct {
+	allowDataLoss bool
+}
+
+type RollbackOption func(*rollbackOptions)
+
+// Runs down migrations even when they drop tables or columns
+func AllowDataLoss() RollbackOption {
+	return func(o *rollbackOptions) {
+		o.allowDataLoss = true
+	}
+}
+
+// Returns the first statement that drops data, empty when there is none.
+// Comments are ignored
+func destructiveStatement(sql string) string {
+	for _, stmt := range strings.Split(commentPattern.ReplaceAllString(sql, " "), ";") {
+		stmt = strings.Join(strings.Fields(stmt), " ")
+		if destructivePattern.MatchString(stmt) || dropsColumn(stmt) {
+			return stmt
+		}
+	}
+	return ""
+}
+
+// ALTER TABLE t DROP c, Postgres doesn't require the COLUMN keyword
+func dropsColumn(stmt string) bool {
+	if !alterTablePattern.MatchString(stmt) {
+		return false
+	}
+
+	for _, match := range alterDropPattern.FindAllStringSubmatch(stmt, -1) {
+		if !nonDestructiveDrops[strings.ToUpper(match[1])] {
+			return true
+		}
+	}
+	return false
+}
+
+func checkDestructive(migrations []MigrationFile) error {
+	for _, mig := range migrations {
+		if stmt := destructiveStatement(mig.DownSQL); stmt != "" {
+			return fmt.Errorf("%w: version %d (%s) runs %q, pass AllowDataLoss to roll it back",
+				ErrDestructiveRollback, mig.Version, mig.DownFilePath, stmt)
+		}
+	}
+	return nil
+}
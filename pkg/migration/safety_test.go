@@ -0,0 +1,95 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDestructiveStatement(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{"drop table", "DROP TABLE users;", "DROP TABLE users"},
+		{"drop table lowercase", "drop table if exists users", "drop table if exists users"},
+		{"truncate", "TRUNCATE audit_log", "TRUNCATE audit_log"},
+		{"drop column", "ALTER TABLE users\n  DROP COLUMN nickname;", "ALTER TABLE users DROP COLUMN nickname"},
+		{"drop without column keyword", "ALTER TABLE users DROP nickname;", "ALTER TABLE users DROP nickname"},
+		{"drop if exists without column keyword", "ALTER TABLE users DROP IF EXISTS nickname", "ALTER TABLE users DROP IF EXISTS nickname"},
+		{"second statement", "DROP INDEX idx_users_email; ALTER TABLE users DROP email", "ALTER TABLE users DROP email"},
+
+		{"drop index", "DROP INDEX idx_users_email;", ""},
+		{"drop constraint", "ALTER TABLE users DROP CONSTRAINT users_email_key;", ""},
+		{"drop default", "ALTER TABLE users ALTER COLUMN role DROP DEFAULT;", ""},
+		{"drop not null", "ALTER TABLE users ALTER COLUMN role DROP NOT NULL;", ""},
+		{"line comment", "-- DROP TABLE users\nDELETE FROM settings WHERE key = 'x';", ""},
+		{"block comment", "/* DROP TABLE users;\n   TRUNCATE users */\nDROP INDEX idx_users_email;", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := destructiveStatement(tt.sql); got != tt.want {
+				t.Errorf("destructiveStatement() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollbackSafeDown(t *testing.T) {
+	dir := t.TempDir()
+	writeMigrations(t, dir, "core", map[string]string{
+		"000001_add_index.up.sql":   "CREATE INDEX idx_users_email ON users (email);",
+		"000001_add_index.down.sql": "DROP INDEX idx_users_email;",
+	})
+
+	fake, db := newFakeDB(t)
+	fake.applied("core", 1)
+
+	if _, err := NewManager(db, dir).Rollback(context.Background(), "core", 1); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+
+	if !slices.Contains(fake.statements(), "DROP INDEX idx_users_email;") {
+		t.Errorf("down migration not executed: %q", fake.statements())
+	}
+	if got := fake.recorded("core"); len(got) != 0 {
+		t.Errorf("recorded versions = %v, want none", got)
+	}
+}
+
+func TestRollbackDestructiveDown(t *testing.T) {
+	dir := t.TempDir()
+	writeMigrations(t, dir, "core", map[string]string{
+		"000001_users.up.sql":          "CREATE TABLE users (id uuid);",
+		"000001_users.down.sql":        "DROP TABLE users;",
+		"000002_add_index.up.sql":      "CREATE INDEX idx_users_id ON users (id);",
+		"000002_add_index.down.sql":    "DROP INDEX idx_users_id;",
+		"000003_add_nickname.up.sql":   "ALTER TABLE users ADD COLUMN nickname text;",
+		"000003_add_nickname.down.sql": "ALTER TABLE users DROP nickname;",
+	})
+
+	fake, db := newFakeDB(t)
+	fake.applied("core", 1, 2, 3)
+	manager := NewManager(db, dir)
+
+	_, err := manager.Rollback(context.Background(), "core", 3)
+	if !errors.Is(err, ErrDestructiveRollback) {
+		t.Fatalf("err = %v, want %v", err, ErrDestructiveRollback)
+	}
+	if len(fake.statements()) != 0 {
+		t.Errorf("statements ran although the rollback was refused: %q", fake.statements())
+	}
+	if got := fake.recorded("core"); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("recorded versions = %v, want [1 2 3]", got)
+	}
+
+	if _, err := manager.Rollback(context.Background(), "core", 3, AllowDataLoss()); err != nil {
+		t.Fatalf("Rollback with AllowDataLoss: %v", err)
+	}
+	if got := fake.recorded("core"); len(got) != 0 {
+		t.Errorf("recorded versions = %v, want none", got)
+	}
+}
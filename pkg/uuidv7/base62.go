@@ -0,0 +1,61 @@
+package uuidv7
+
+import (
+	"errors"
+	"strings"
+)
+
+// Alphabet is in ASCII order so encoded strings sort like the underlying UUIDs
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// 62^22 > 2^128, so every UUID fits in 22 characters
+const base62Length = 22
+
+var ErrInvalidBase62 = errors.New("invalid base62 uuid")
+
+// Encodes UUID as fixed-width 22 character base62 string.
+// Zero padding keeps lexicographic order equal to UUID (and so time) order
+func ToBase62(u UUID) string {
+	var out [base62Length]byte
+	num := u
+
+	for i := base62Length - 1; i >= 0; i-- {
+		// Divide the 128-bit big-endian number by 62 in place
+		remainder := 0
+		for j := range num {
+			acc := remainder<<8 | int(num[j])
+			num[j] = byte(acc / 62)
+			remainder = acc % 62
+		}
+		out[i] = base62Alphabet[remainder]
+	}
+
+	return string(out[:])
+}
+
+func FromBase62(s string) (UUID, error) {
+	if len(s) != base62Length {
+		return Nil, ErrInvalidBase62
+	}
+
+	var u UUID
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base62Alphabet, s[i])
+		if digit < 0 {
+			return Nil, ErrInvalidBase62
+		}
+
+		// Multiply the 128-bit number by 62 and add digit
+		carry := digit
+		for j := len(u) - 1; j >= 0; j-- {
+			acc := int(u[j])*62 + carry
+			u[j] = byte(acc)
+			carry = acc >> 8
+		}
+		if carry != 0 {
+			return Nil, ErrInvalidBase62 // Overflows 128 bits
+		}
+	}
+
+	return u, nil
+}
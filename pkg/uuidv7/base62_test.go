@@ -0,0 +1,50 @@
+package uuidv7
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBase62PreservesOrdering(t *testing.T) {
+	g := NewGenerator()
+
+	previous := ToBase62(g.Next())
+	for i := range 1000 {
+		encoded := ToBase62(g.Next())
+		if len(encoded) != base62Length {
+			t.Fatalf("id %d: %q has length %d, want %d", i, encoded, len(encoded), base62Length)
+		}
+		if encoded <= previous {
+			t.Fatalf("id %d: %q does not sort after %q", i, encoded, previous)
+		}
+		previous = encoded
+	}
+}
+
+func TestBase62RoundTrip(t *testing.T) {
+	maxID := MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
+
+	for _, id := range []UUID{Nil, maxID, New()} {
+		decoded, err := FromBase62(ToBase62(id))
+		if err != nil {
+			t.Fatalf("FromBase62(%s): %v", id, err)
+		}
+		if decoded != id {
+			t.Errorf("round trip = %s, want %s", decoded, id)
+		}
+	}
+}
+
+func TestFromBase62Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"short",
+		strings.Repeat("0", base62Length-1) + "!",
+		strings.Repeat("z", base62Length), // Above 2^128
+	} {
+		if _, err := FromBase62(s); !errors.Is(err, ErrInvalidBase62) {
+			t.Errorf("FromBase62(%q) err = %v, want %v", s, err, ErrInvalidBase62)
+		}
+	}
+}
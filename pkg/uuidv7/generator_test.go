@@ -0,0 +1,153 @@
+package uuidv7
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+	"time"
+)
+
+func randomBytes(t *testing.T) []byte {
+	t.Helper()
+
+	random := make([]byte, 10)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatal(err)
+	}
+	return random
+}
+
+func TestExtractSequenceWithinMillisecond(t *testing.T) {
+	g := NewGenerator()
+	ms := time.Now().UnixMilli()
+
+	var previous uint16
+	for i := range 100 {
+		id := g.next(ms, randomBytes(t))
+
+		if got := ExtractTime(id).UnixMilli(); got != ms {
+			t.Fatalf("id %d: timestamp = %d, want %d", i, got, ms)
+		}
+
+		seq, ok := ExtractSequence(id)
+		if !ok {
+			t.Fatalf("id %d: ExtractSequence not ok for %s", i, id)
+		}
+		if i > 0 && seq != previous+1 {
+			t.Fatalf("id %d: sequence = %d, want %d", i, seq, previous+1)
+		}
+		previous = seq
+	}
+}
+
+func TestExtractSequenceSeededWithRoomToIncrement(t *testing.T) {
+	g := NewGenerator()
+	random := randomBytes(t)
+	random[8], random[9] = 0xff, 0xff
+
+	seq, _ := ExtractSequence(g.next(time.Now().UnixMilli(), random))
+	if seq > counterSeedMask {
+		t.Errorf("seed = %d, want at most %d", seq, counterSeedMask)
+	}
+}
+
+func TestExtractSequenceRejectsOtherVersions(t *testing.T) {
+	v4 := MustParse(v4String)
+	if _, ok := ExtractSequence(v4); ok {
+		t.Error("ExtractSequence ok for a v4 UUID")
+	}
+}
+
+func TestGeneratorStrictlyAscending(t *testing.T) {
+	g := NewGenerator()
+
+	previous := g.Next()
+	for i := range 10000 {
+		id := g.Next()
+		if bytes.Compare(id[:], previous[:]) <= 0 {
+			t.Fatalf("id %d: %s not after %s", i, id, previous)
+		}
+		if !IsV7(id) {
+			t.Fatalf("id %d: %s is not v7", i, id)
+		}
+		previous = id
+	}
+}
+
+func TestGeneratorCounterOverflowAdvancesTimestamp(t *testing.T) {
+	g := NewGenerator()
+	ms := time.Now().UnixMilli()
+
+	previous := g.next(ms, randomBytes(t))
+	for i := range maxCounter + 1 {
+		id := g.next(ms, randomBytes(t))
+		if bytes.Compare(id[:], previous[:]) <= 0 {
+			t.Fatalf("id %d: %s not after %s", i, id, previous)
+		}
+		previous = id
+	}
+
+	if got := ExtractTime(previous).UnixMilli(); got != ms+1 {
+		t.Errorf("timestamp after overflow = %d, want %d", got, ms+1)
+	}
+}
+
+func TestGeneratorClockMovingBackwards(t *testing.T) {
+	g := NewGenerator()
+	ms := time.Now().UnixMilli()
+
+	first := g.next(ms, randomBytes(t))
+	second := g.next(ms-1000, randomBytes(t))
+
+	if bytes.Compare(second[:], first[:]) <= 0 {
+		t.Errorf("%s after a clock step back is not after %s", second, first)
+	}
+}
+
+func TestNewBatchOrderedAndUnique(t *testing.T) {
+	batch := NewBatch(5000)
+	if len(batch) != 5000 {
+		t.Fatalf("len = %d, want 5000", len(batch))
+	}
+
+	seen := make(map[UUID]bool, len(batch))
+	for i, id := range batch {
+		if seen[id] {
+			t.Fatalf("id %d: %s repeated", i, id)
+		}
+		seen[id] = true
+
+		if i > 0 && bytes.Compare(id[:], batch[i-1][:]) <= 0 {
+			t.Fatalf("id %d: %s not after %s", i, id, batch[i-1])
+		}
+	}
+
+	// The next batch continues after the last one
+	next := NewBatch(1)[0]
+	if last := batch[len(batch)-1]; bytes.Compare(next[:], last[:]) <= 0 {
+		t.Errorf("next batch %s not after %s", next, last)
+	}
+}
+
+func TestNewBatchEmpty(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		if got := NewBatch(n); len(got) != 0 {
+			t.Errorf("NewBatch(%d) = %v, want empty", n, got)
+		}
+	}
+}
+
+func BenchmarkNewBatch(b *testing.B) {
+	for b.Loop() {
+		NewBatch(1000)
+	}
+}
+
+func BenchmarkNewSequential(b *testing.B) {
+	g := NewGenerator()
+	for b.Loop() {
+		for range 1000 {
+			g.Next()
+		}
+	}
+}
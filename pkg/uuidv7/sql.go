@@ -0,0 +1,25 @@
+package uuidv7
+
+import "database/sql/driver"
+
+// Scans a database value (string or bytes) into UUID. NULL scans into Nil
+func Scan(src any) (UUID, error) {
+	if src == nil {
+		return Nil, nil
+	}
+
+	var u UUID
+	if err := u.Scan(src); err != nil {
+		return Nil, err
+	}
+	return u, nil
+}
+
+// Returns canonical string form for storage. Nil is stored as NULL,
+// which makes it symmetric with Scan
+func Value(u UUID) (driver.Value, error) {
+	if u == Nil {
+		return nil, nil
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,94 @@
+package uuidv7
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+func TestScan(t *testing.T) {
+	id := New()
+
+	tests := []struct {
+		name string
+		src  any
+		want UUID
+	}{
+		{"NULL", nil, Nil},
+		{"string", id.String(), id},
+		{"bytes", []byte(id.String()), id},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Scan(tt.src)
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Scan = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Scan("not-a-uuid"); err == nil {
+		t.Error("Scan accepted an invalid string")
+	}
+}
+
+func TestValueRoundTrip(t *testing.T) {
+	id := New()
+
+	value, err := Value(id)
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+	if got, _ := Scan(value); got != id {
+		t.Errorf("round trip = %s, want %s", got, id)
+	}
+
+	if value, _ := Value(Nil); value != nil {
+		t.Errorf("Value(Nil) = %v, want NULL", value)
+	}
+}
+
+// Runs against Postgres only when NEXUS_TEST_DATABASE_URL is set
+func TestRoundTripPostgres(t *testing.T) {
+	dsn := os.Getenv("NEXUS_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("NEXUS_TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	// Temp tables live on one connection
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(`CREATE TEMP TABLE uuidv7_test (id uuid PRIMARY KEY, parent_id uuid)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	id := New()
+	idValue, _ := Value(id)
+	parentValue, _ := Value(Nil)
+	if _, err := db.Exec(`INSERT INTO uuidv7_test (id, parent_id) VALUES ($1, $2)`, idValue, parentValue); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	var rawID, rawParent any
+	if err := db.QueryRow(`SELECT id, parent_id FROM uuidv7_test`).Scan(&rawID, &rawParent); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+
+	if got, err := Scan(rawID); err != nil || got != id {
+		t.Errorf("id = %s (err %v), want %s", got, err, id)
+	}
+	if got, err := Scan(rawParent); err != nil || got != Nil {
+		t.Errorf("parent_id = %s (err %v), want Nil", got, err)
+	}
+}
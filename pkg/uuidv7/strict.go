@@ -0,0 +1,42 @@
+package uuidv7
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// UUID that only accepts version 7 when decoded from JSON, for request
+// fields that must carry IDs issued by this service
+type StrictUUID UUID
+
+func (u StrictUUID) UUID() UUID {
+	return UUID(u)
+}
+
+func (u StrictUUID) String() string {
+	return UUID(u).String()
+}
+
+func (u StrictUUID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(UUID(u).String())
+}
+
+// JSON null leaves the value unchanged, like the standard library does
+func (u *StrictUUID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("uuid must be a string: %w", err)
+	}
+
+	parsed, err := ParseV7(s)
+	if err != nil {
+		return err
+	}
+
+	*u = StrictUUID(parsed)
+	return nil
+}
@@ -0,0 +1,68 @@
+package uuidv7
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type createOrderRequest struct {
+	CustomerID StrictUUID `json:"customer_id"`
+}
+
+func TestStrictUUIDAcceptsV7(t *testing.T) {
+	id := New()
+
+	var req createOrderRequest
+	if err := json.Unmarshal([]byte(`{"customer_id":"`+id.String()+`"}`), &req); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if req.CustomerID.UUID() != id {
+		t.Errorf("customer_id = %s, want %s", req.CustomerID, id)
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"customer_id":"` + id.String() + `"}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+}
+
+func TestStrictUUIDRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		wantV7 bool // Error is ErrNotV7
+	}{
+		{"v4", `{"customer_id":"` + v4String + `"}`, true},
+		{"malformed", `{"customer_id":"not-a-uuid"}`, false},
+		{"number", `{"customer_id":42}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req createOrderRequest
+			err := json.Unmarshal([]byte(tt.body), &req)
+			if err == nil {
+				t.Fatal("Unmarshal succeeded")
+			}
+			if errors.Is(err, ErrNotV7) != tt.wantV7 {
+				t.Errorf("err = %v, ErrNotV7 = %v", err, tt.wantV7)
+			}
+		})
+	}
+}
+
+func TestStrictUUIDNullLeavesValue(t *testing.T) {
+	id := New()
+	req := createOrderRequest{CustomerID: StrictUUID(id)}
+
+	if err := json.Unmarshal([]byte(`{"customer_id":null}`), &req); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if req.CustomerID.UUID() != id {
+		t.Errorf("customer_id = %s, want it unchanged", req.CustomerID)
+	}
+}
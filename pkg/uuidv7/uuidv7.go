@@ -1,13 +1,17 @@
 package uuidv7
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/binary"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var ErrNotV7 = errors.New("uuid is not version 7")
+
 func New() uuid.UUID {
 	return NewWithTime(time.Now())
 }
@@ -47,14 +51,57 @@ func ExtractTime(u uuid.UUID) time.Time {
 	return time.UnixMilli(int64(unixMs))
 }
 
+// Like ExtractTime but returns ErrNotV7 instead of zero time for other versions
+func ExtractTimeChecked(u UUID) (time.Time, error) {
+	if !IsV7(u) {
+		return time.Time{}, ErrNotV7
+	}
+	return ExtractTime(u), nil
+}
+
 func IsV7(u uuid.UUID) bool {
 	return u[6]>>4 == 0x07
 }
 
+// Returns -1, 0 or 1. Byte order matches creation time order for v7
+func Compare(a, b UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+func Less(a, b UUID) bool {
+	return Compare(a, b) < 0
+}
+
+func Min(a, b UUID) UUID {
+	if Less(b, a) {
+		return b
+	}
+	return a
+}
+
+func Max(a, b UUID) UUID {
+	if Less(a, b) {
+		return b
+	}
+	return a
+}
+
 func Parse(s string) (UUID, error) {
 	return uuid.Parse(s)
 }
 
+// Parses UUID and rejects any version other than 7
+func ParseV7(s string) (UUID, error) {
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return Nil, err
+	}
+	if !IsV7(u) {
+		return Nil, ErrNotV7
+	}
+	return u, nil
+}
+
 func MustParse(s string) UUID {
 	return uuid.MustParse(s)
 }
@@ -0,0 +1,72 @@
+package uuidv7
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const v4String = "2f1c3b0e-8f4e-4c1a-9b2d-3a4e5f607182"
+
+func TestCompareFollowsCreationOrder(t *testing.T) {
+	g := NewGenerator()
+
+	// Created back to back, mostly within the same millisecond
+	earlier := g.Next()
+	later := g.Next()
+
+	if Compare(earlier, later) != -1 || Compare(later, earlier) != 1 || Compare(earlier, earlier) != 0 {
+		t.Errorf("Compare(%s, %s) is not ordered by creation", earlier, later)
+	}
+	if !Less(earlier, later) || Less(later, earlier) {
+		t.Errorf("Less(%s, %s) is not ordered by creation", earlier, later)
+	}
+	if Min(later, earlier) != earlier || Max(earlier, later) != later {
+		t.Errorf("Min/Max picked the wrong ID")
+	}
+}
+
+func TestCompareAcrossTimestamps(t *testing.T) {
+	now := time.Now()
+	earlier := NewWithTime(now)
+	later := NewWithTime(now.Add(time.Millisecond))
+
+	if !Less(earlier, later) {
+		t.Errorf("ID from %v is not less than one from 1ms later", now)
+	}
+}
+
+func TestParseV7(t *testing.T) {
+	id := New()
+
+	parsed, err := ParseV7(id.String())
+	if err != nil {
+		t.Fatalf("ParseV7(v7): %v", err)
+	}
+	if parsed != id {
+		t.Errorf("ParseV7 = %s, want %s", parsed, id)
+	}
+
+	if _, err := ParseV7(v4String); !errors.Is(err, ErrNotV7) {
+		t.Errorf("ParseV7(v4) err = %v, want %v", err, ErrNotV7)
+	}
+	if _, err := ParseV7("not-a-uuid"); err == nil || errors.Is(err, ErrNotV7) {
+		t.Errorf("ParseV7(garbage) err = %v, want a parse error", err)
+	}
+}
+
+func TestExtractTimeChecked(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+
+	got, err := ExtractTimeChecked(NewWithTime(now))
+	if err != nil {
+		t.Fatalf("ExtractTimeChecked(v7): %v", err)
+	}
+	if !got.Equal(now) {
+		t.Errorf("time = %v, want %v", got, now)
+	}
+
+	if _, err := ExtractTimeChecked(MustParse(v4String)); !errors.Is(err, ErrNotV7) {
+		t.Errorf("ExtractTimeChecked(v4) err = %v, want %v", err, ErrNotV7)
+	}
+}
@@ -5,3 +5,10 @@ const (
 	ServiceVersion = "0.1.2"
 	ServiceID      = "nexus"
 )
+
+// Set at build time, e.g.
+// go build -ldflags "-X nexus/pkg/version.GitCommit=$(git rev-parse HEAD) -X nexus/pkg/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	GitCommit = "unknown"
+	BuildTime = "unknown"
+)